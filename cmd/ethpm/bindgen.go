@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/ethpm/ethpm-go/pkg/bindgen"
+)
+
+const bindgenUsage = "bindgen <manifest> --pkg <name> [--out <file>]"
+
+// runBindgen generates Go bindings for every contract type in a manifest
+func runBindgen(args []string) (err error) {
+	fs := flag.NewFlagSet("bindgen", flag.ContinueOnError)
+	pkg := fs.String("pkg", "", "Go package name of the generated bindings")
+	out := fs.String("out", "", "File to write the bindings to, stdout if empty")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if (len(positional) != 1) || (*pkg == "") {
+		err = errors.New("Usage: ethpm " + bindgenUsage)
+		return
+	}
+	p, err := readManifest(positional[0])
+	if err != nil {
+		return
+	}
+	code, err := bindgen.Generate(p, *pkg)
+	if err != nil {
+		return
+	}
+	err = writeOutput(*out, code)
+	return
+}
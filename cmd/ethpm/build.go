@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+const buildUsage = "build [<project dir>] [--config <file>] [--out <dir>] [--solc-cache <dir>] [--solc-mirror <url>]"
+
+// runBuild builds a validated manifest from the ethpm.yaml project file and
+// writes it as ethpm.json, along with the checksums of its sources
+func runBuild(args []string) (err error) {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	config := fs.String("config", "", "Project file, ethpm.yaml in the project directory if empty")
+	out := fs.String("out", "", "Directory to write ethpm.json to, the project directory if empty")
+	solccache := fs.String("solc-cache", "", "Directory to install solc versions to, ~/.ethpm/solc if empty")
+	solcmirror := fs.String("solc-mirror", "", "Url or directory to install solc versions from")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) > 1 {
+		err = errors.New("Usage: ethpm " + buildUsage)
+		return
+	}
+	projectdir := "."
+	if len(positional) == 1 {
+		projectdir = positional[0]
+	}
+	if *config == "" {
+		*config = filepath.Join(projectdir, ethpm.ProjectFile)
+	}
+	if *out == "" {
+		*out = projectdir
+	}
+	pc, err := ethpm.ReadProjectConfig(*config)
+	if err != nil {
+		return
+	}
+	var manager *solcutils.SolcManager
+	if (pc.Compiler != nil) && (pc.Compiler.Version != "") {
+		if manager, err = solcutils.NewSolcManager(*solccache, *solcmirror); err != nil {
+			return
+		}
+	}
+	p, err := pc.Build(projectdir, manager)
+	if err != nil {
+		return
+	}
+	manifest, err := p.Write()
+	if err != nil {
+		err = fmt.Errorf("Could not write manifest: '%v'", err)
+		return
+	}
+	if err = writeOutput(filepath.Join(*out, "ethpm.json"), manifest); err != nil {
+		return
+	}
+	if len(p.SourceChecksums) > 0 {
+		err = p.WriteChecksums(*out)
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/gethutils"
+)
+
+const callUsage = "call <manifest> <chain> <contract> <method> [args...] [--rpc <endpoint>] " +
+	"[--keystore <file> --password <file> | --key <file>]"
+
+// runCall calls or sends a transaction to a deployed contract instance of a
+// package. Constant methods are called and their results printed one per line,
+// other methods are sent as transactions and the transaction hash is printed.
+func runCall(args []string) (err error) {
+	fs := flag.NewFlagSet("call", flag.ContinueOnError)
+	rpcendpoint := fs.String("rpc", "", "RPC endpoint, the default geth ipc if empty")
+	keystorefile := fs.String("keystore", "", "Keystore file of the signing account")
+	passwordfile := fs.String("password", "", "File containing the keystore password")
+	keyfile := fs.String("key", "", "File containing a hex encoded private key")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) < 4 {
+		err = errors.New("Usage: ethpm " + callUsage)
+		return
+	}
+	p, err := readManifest(positional[0])
+	if err != nil {
+		return
+	}
+	h, err := p.Instance(positional[1], positional[2], filepath.Dir(positional[0]))
+	if err != nil {
+		return
+	}
+	if *rpcendpoint != "" {
+		if h.Backend, err = ethclient.Dial(*rpcendpoint); err != nil {
+			err = fmt.Errorf("Could not connect to '%v': '%v'", *rpcendpoint, err)
+			return
+		}
+	}
+	method, values := positional[3], positional[4:]
+	if _, ok := h.ABI.Methods[method]; !ok {
+		err = fmt.Errorf("No method '%v' in contract '%v'", method, positional[2])
+		return
+	}
+	if h.IsConstant(method) {
+		results, retErr := h.Call(context.Background(), method, values...)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		for _, v := range results {
+			fmt.Println(ethcontract.FormatValue(v))
+		}
+		return
+	}
+	opts, err := transactOpts(*keystorefile, *passwordfile, *keyfile)
+	if err != nil {
+		return
+	}
+	tx, err := h.Transact(opts, method, values...)
+	if err != nil {
+		return
+	}
+	fmt.Println(tx.Hash().Hex())
+	return
+}
+
+// transactOpts returns the signer for either a keystore file or a raw key file
+func transactOpts(keystorefile string, passwordfile string, keyfile string) (opts *bind.TransactOpts, err error) {
+	switch {
+	case keyfile != "":
+		return gethutils.RawKeyTransactor(keyfile)
+	case keystorefile != "":
+		password := ""
+		if passwordfile != "" {
+			b, retErr := ioutil.ReadFile(passwordfile)
+			if retErr != nil {
+				err = fmt.Errorf("Could not read password file: '%v'", retErr)
+				return
+			}
+			password = strings.TrimRight(string(b), "\r\n")
+		} else {
+			password = gethutils.GetPassword()
+		}
+		return gethutils.KeystoreTransactor(keystorefile, password)
+	}
+	err = errors.New("Sending a transaction requires --keystore or --key")
+	return
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+)
+
+const decodeUsage = "decode <manifest> [--project <dir>] (--calldata <hex> [--return <hex>] | " +
+	"--revert <hex> | --topics <hex,...> [--data <hex>])"
+
+// runDecode decodes call data, return data, revert data or a log using the abis
+// of a package and its installed build dependencies
+func runDecode(args []string) (err error) {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	projectdir := fs.String("project", "", "Project directory with the installed dependencies")
+	calldata := fs.String("calldata", "", "Transaction or call input data")
+	returndata := fs.String("return", "", "Return data of the call given by --calldata")
+	revertdata := fs.String("revert", "", "Revert data of a failed call")
+	topics := fs.String("topics", "", "Comma separated topics of a log")
+	data := fs.String("data", "", "Data of the log given by --topics")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		err = errors.New("Usage: ethpm " + decodeUsage)
+		return
+	}
+	p, err := readManifest(positional[0])
+	if err != nil {
+		return
+	}
+	d, err := p.NewDecoder(*projectdir)
+	if err != nil {
+		return
+	}
+	var decoded *ethpm.Decoded
+	switch {
+	case *topics != "":
+		var hashes []common.Hash
+		for _, v := range strings.Split(*topics, ",") {
+			hashes = append(hashes, common.HexToHash(strings.TrimSpace(v)))
+		}
+		decoded, err = d.DecodeLog(hashes, common.FromHex(*data))
+	case *revertdata != "":
+		decoded, err = d.DecodeRevert(common.FromHex(*revertdata))
+	case *returndata != "":
+		decoded, err = d.DecodeReturn(common.FromHex(*calldata), common.FromHex(*returndata))
+	case *calldata != "":
+		decoded, err = d.DecodeCalldata(common.FromHex(*calldata))
+	default:
+		err = errors.New("Usage: ethpm " + decodeUsage)
+	}
+	if err != nil {
+		return
+	}
+	fmt.Println(decoded)
+	return
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/docgen"
+)
+
+const docsUsage = "docs <manifest> [--format md|html] [--out <dir>]"
+
+// runDocs writes a documentation page for every contract type in a manifest
+func runDocs(args []string) (err error) {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	format := fs.String("format", docgen.Markdown, "Output format, md or html")
+	out := fs.String("out", "docs", "Directory to write the pages to")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		err = errors.New("Usage: ethpm " + docsUsage)
+		return
+	}
+	p, err := readManifest(positional[0])
+	if err != nil {
+		return
+	}
+	pages, err := docgen.Generate(p, *format)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(*out, os.ModePerm); err != nil {
+		return
+	}
+	// Page names come from contract type names, which must not leave the directory
+	for name := range pages {
+		if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+			err = fmt.Errorf("Invalid contract type name for page '%v'", name)
+			return
+		}
+	}
+	for name, page := range pages {
+		if err = ioutil.WriteFile(filepath.Join(*out, name), []byte(page), 0644); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+const extractUsage = "extract <manifest> <dir> [--ipfs-gateway <url>]"
+
+// runExtract writes the sources of a package and of its dependencies to a
+// directory, verifying the checksums recorded next to the manifest
+func runExtract(args []string) (err error) {
+	fs := flag.NewFlagSet("extract", flag.ContinueOnError)
+	ipfs := fs.String("ipfs-gateway", "", "IPFS gateway to fetch ipfs sources from")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 2 {
+		err = errors.New("Usage: ethpm " + extractUsage)
+		return
+	}
+	p, err := readManifest(positional[0])
+	if err != nil {
+		return
+	}
+	manifestdir := filepath.Dir(positional[0])
+	if _, retErr := os.Stat(filepath.Join(manifestdir, ethpm.ChecksumFile)); retErr == nil {
+		if err = p.ReadChecksums(manifestdir); err != nil {
+			return
+		}
+	}
+	written, err := p.ExtractSources(positional[1], manifestdir, solcutils.NewMetadataGateway(*ipfs, ""))
+	if err != nil {
+		return
+	}
+	for _, w := range written {
+		fmt.Println(w)
+	}
+	return
+}
@@ -1,24 +1,108 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"sort"
 
 	"github.com/ethpm/ethpm-go/pkg/ethpm"
 )
 
-func main() {
-	pm := `{"manifest_version":"2","package_name":"array-utils","version":"1.2.7"}`
-	p := ethpm.PackageManifest{}
+// command is an ethpm subcommand which takes the arguments following its name
+type command struct {
+	run   func(args []string) (err error)
+	usage string
+}
+
+var commands = map[string]*command{
+	"bindgen": {runBindgen, bindgenUsage},
+	"build":   {runBuild, buildUsage},
+	"call":    {runCall, callUsage},
+	"decode":  {runDecode, decodeUsage},
+	"docs":    {runDocs, docsUsage},
+	"extract": {runExtract, extractUsage},
+	"verify":  {runVerify, verifyUsage},
+}
 
-	if err := p.Read(pm); err != nil {
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	c, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command '%v'\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := c.run(os.Args[2:]); err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("%+v\n", p)
+}
 
-	if newManifest, err := p.Write(); err != nil {
-		log.Fatal(err)
-	} else {
-		fmt.Println(newManifest)
+func usage() {
+	names := make([]string, 0, len(commands))
+	for k := range commands {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage:")
+	for _, k := range names {
+		fmt.Fprintf(os.Stderr, "  ethpm %v\n", commands[k].usage)
+	}
+}
+
+// parseArgs parses the flags of a subcommand wherever they appear among its
+// arguments and returns the remaining positional arguments. Everything after
+// "--" is positional, which allows values such as negative numbers.
+func parseArgs(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	var rest []string
+	for i, v := range args {
+		if v == "--" {
+			args, rest = args[:i], args[i+1:]
+			break
+		}
+	}
+	for {
+		if err = fs.Parse(args); err != nil {
+			return
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	positional = append(positional, rest...)
+	return
+}
+
+// readManifest reads and parses the package manifest at the given path
+func readManifest(manifestpath string) (p *ethpm.PackageManifest, err error) {
+	b, err := ioutil.ReadFile(manifestpath)
+	if err != nil {
+		err = fmt.Errorf("Could not read manifest: '%v'", err)
+		return
+	}
+	p = &ethpm.PackageManifest{}
+	if err = p.Read(string(b)); err != nil {
+		err = fmt.Errorf("Could not parse manifest '%v': '%v'", manifestpath, err)
+	}
+	return
+}
+
+// writeOutput writes s to the given file, or to stdout if the path is empty
+func writeOutput(outpath string, s string) (err error) {
+	if outpath == "" {
+		_, err = fmt.Print(s)
+		return
+	}
+	if err = ioutil.WriteFile(outpath, []byte(s), 0644); err != nil {
+		err = fmt.Errorf("Could not write '%v': '%v'", outpath, err)
 	}
+	return
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+const verifyUsage = "verify <manifest> [--ipfs-gateway <url>] [--solc-cache <dir>] [--solc-mirror <url>] [--verify-sources]"
+
+// runVerify recompiles every contract type of a manifest with the compiler
+// recorded in it and prints a verdict for each. With --verify-sources every
+// source must match a recorded checksum first.
+func runVerify(args []string) (err error) {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	ipfs := fs.String("ipfs-gateway", "", "IPFS gateway to fetch ipfs sources from")
+	solccache := fs.String("solc-cache", "", "Directory to install solc versions to, ~/.ethpm/solc if empty")
+	solcmirror := fs.String("solc-mirror", "", "Url or directory to install solc versions from")
+	verifysources := fs.Bool("verify-sources", false, "Require every source to match its recorded checksum")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		err = errors.New("Usage: ethpm " + verifyUsage)
+		return
+	}
+	p, err := readManifest(positional[0])
+	if err != nil {
+		return
+	}
+	manifestdir := filepath.Dir(positional[0])
+	if _, retErr := os.Stat(filepath.Join(manifestdir, ethpm.ChecksumFile)); retErr == nil {
+		if err = p.ReadChecksums(manifestdir); err != nil {
+			return
+		}
+	}
+	gateway := solcutils.NewMetadataGateway(*ipfs, "")
+	if *verifysources {
+		if err = p.VerifySources(manifestdir, gateway); err != nil {
+			return
+		}
+	}
+	manager, err := solcutils.NewSolcManager(*solccache, *solcmirror)
+	if err != nil {
+		return
+	}
+	verdicts, err := p.VerifyContractTypes(manifestdir, gateway, manager)
+	if err != nil {
+		return
+	}
+	failed := 0
+	for _, v := range verdicts {
+		fmt.Println(v)
+		if !v.Valid() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		err = fmt.Errorf("%v of %v contract types failed verification", failed, len(verdicts))
+	}
+	return
+}
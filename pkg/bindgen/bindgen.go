@@ -0,0 +1,220 @@
+/*
+The MIT License (MIT)
+https://github.com/ethpm/ethpm-go/blob/master/LICENSE
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+/*
+Package bindgen generates Go contract bindings for the contract types of a
+package manifest using go-ethereum's abigen templates. Contract types which link
+against libraries get a generated Link function returning their bytecode with the
+placeholders filled in, which their Deploy function takes, and every deployment
+of the package is listed by chain.
+*/
+package bindgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+)
+
+var nonIdentifierRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Generate takes a package manifest and the name of the Go package to create,
+// then returns the source of the bindings for every contract type with an abi.
+// Contract types with deployment bytecode also get a Deploy function. When their
+// bytecode has link references they get a Link function as well, and their
+// Deploy function takes the linked bytecode, refusing it while any placeholder
+// remains.
+func Generate(p *ethpm.PackageManifest, pkg string) (code string, err error) {
+	var types, abis, bytecodes, linkedtypes []string
+	var linked bytes.Buffer
+
+	names := make([]string, 0, len(p.ContractTypes))
+	for k := range p.ContractTypes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ct := p.ContractTypes[name]
+		if len(ct.ABI) == 0 {
+			continue
+		}
+		abijson, retErr := ethcontract.GethABIJSON(ct.ABI)
+		if retErr != nil {
+			err = fmt.Errorf("Error writing abi for '%v': '%v'", name, retErr)
+			return
+		}
+		bin, placeholders, retErr := deploymentBin(ct.DeploymentBytecode)
+		if retErr != nil {
+			err = fmt.Errorf("Error adding link placeholders for '%v': '%v'", name, retErr)
+			return
+		}
+		typename := TypeName(name)
+		types = append(types, typename)
+		abis = append(abis, abijson)
+		bytecodes = append(bytecodes, bin)
+		if len(placeholders) > 0 {
+			writeLinkFunction(&linked, typename, placeholders)
+			linkedtypes = append(linkedtypes, typename)
+		}
+	}
+	if len(types) == 0 {
+		err = fmt.Errorf("Package '%v' has no contract types with an abi", p.PackageName)
+		return
+	}
+	if code, err = bind.Bind(types, abis, bytecodes, pkg, bind.LangGo); err != nil {
+		err = fmt.Errorf("Error generating bindings: '%v'", err)
+		return
+	}
+	if len(linkedtypes) > 0 {
+		for _, typename := range linkedtypes {
+			if code, err = deployLinked(code, typename); err != nil {
+				return
+			}
+		}
+		code = strings.Replace(code, "import (\n", "import (\n\t\"fmt\"\n", 1)
+		code += linked.String()
+	}
+	code += deploymentsVar(p)
+	b, err := format.Source([]byte(code))
+	if err != nil {
+		err = fmt.Errorf("Error formatting bindings: '%v'", err)
+		return
+	}
+	code = string(b)
+	return
+}
+
+// TypeName takes a contract type name and returns the Go type name used for its
+// binding, following the capitalisation rules of abigen
+func TypeName(name string) string {
+	name = strings.TrimLeft(nonIdentifierRegex.ReplaceAllString(name, "_"), "_")
+	parts := strings.Split(name, "_")
+	for i, v := range parts {
+		if len(v) > 0 {
+			parts[i] = strings.ToUpper(v[:1]) + v[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// deploymentBin returns the deployment bytecode with a placeholder written into
+// every link reference, along with the placeholder used for each library keyed
+// by its fully qualified name. Libraries with a known source get the hashed
+// placeholder of solc 0.5, so libraries of the same name stay apart.
+func deploymentBin(ub *bc.UnlinkedBytecode) (bin string, placeholders map[string]string, err error) {
+	if (ub == nil) || (strings.TrimPrefix(ub.Bytecode, "0x") == "") {
+		return
+	}
+	placeholders = make(map[string]string)
+	for _, lr := range ub.LinkReferences {
+		if (lr.Source != "") && (lr.Length == 20) {
+			placeholders[lr.FullyQualifiedName()] = bc.Placeholder(lr.FullyQualifiedName())
+		} else {
+			placeholders[lr.FullyQualifiedName()] = bc.LegacyPlaceholder(lr.Name)
+		}
+	}
+	bin, err = ub.Link(placeholders)
+	return
+}
+
+// writeLinkFunction writes the placeholders of a contract type and the function
+// returning its bytecode with library addresses in their place
+func writeLinkFunction(w *bytes.Buffer, typename string, placeholders map[string]string) {
+	names := make([]string, 0, len(placeholders))
+	for k := range placeholders {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "\n// %vLinkReferences maps each library %v links against to its placeholder in %vBin.\n", typename, typename, typename)
+	fmt.Fprintf(w, "var %vLinkReferences = map[string]string{\n", typename)
+	for _, k := range names {
+		fmt.Fprintf(w, "%q: %q,\n", k, placeholders[k])
+	}
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "\n// Link%vBin takes the addresses of the deployed libraries keyed by their fully\n", typename)
+	fmt.Fprintf(w, "// qualified \"source:Name\", or by name for link references without a source,\n")
+	fmt.Fprintf(w, "// and returns %vBin with them written in, to be passed to Deploy%v.\n", typename, typename)
+	fmt.Fprintf(w, "func Link%vBin(libraries map[string]common.Address) (string, error) {\n", typename)
+	fmt.Fprintf(w, "bin := %vBin\n", typename)
+	fmt.Fprintf(w, "for name, placeholder := range %vLinkReferences {\n", typename)
+	fmt.Fprintf(w, "address, ok := libraries[name]\n")
+	fmt.Fprintf(w, "if !ok {\n")
+	fmt.Fprintf(w, "return \"\", fmt.Errorf(\"No address for library '%%v'\", name)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "bin = strings.Replace(bin, placeholder, strings.ToLower(address.Hex()[2:]), -1)\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "return bin, nil\n")
+	fmt.Fprintf(w, "}\n")
+	return
+}
+
+// deployLinked rewrites the Deploy function abigen generated for a contract type
+// with link references to take its linked bytecode, as returned by its Link
+// function, and to refuse bytecode in which a placeholder remains
+func deployLinked(code string, typename string) (linked string, err error) {
+	signature := "func Deploy" + typename + "(auth *bind.TransactOpts, backend bind.ContractBackend"
+	start := strings.Index(code, signature)
+	if start < 0 {
+		err = fmt.Errorf("No deploy function generated for '%v'", typename)
+		return
+	}
+	body := start + strings.Index(code[start:], "{\n") + 2
+	check := fmt.Sprintf("for name, placeholder := range %vLinkReferences {\n", typename) +
+		"if strings.Contains(bin, placeholder) {\n" +
+		"return common.Address{}, nil, nil, fmt.Errorf(\"Library '%v' is not linked\", name)\n" +
+		"}\n" +
+		"}\n"
+	deploy := strings.Replace(code[start:body], signature, signature+", bin string", 1) + check +
+		strings.Replace(code[body:], "common.FromHex("+typename+"Bin)", "common.FromHex(bin)", 1)
+	linked = code[:start] + deploy
+	return
+}
+
+// deploymentsVar returns the declaration listing the address of every deployment
+// keyed by BIP122 chain uri and then contract instance name
+func deploymentsVar(p *ethpm.PackageManifest) string {
+	if len(p.Deployments) == 0 {
+		return ""
+	}
+	var w bytes.Buffer
+	chains := make([]string, 0, len(p.Deployments))
+	for k := range p.Deployments {
+		chains = append(chains, k)
+	}
+	sort.Strings(chains)
+	fmt.Fprintf(&w, "\n// Deployments lists the address of every deployment of this package keyed by\n")
+	fmt.Fprintf(&w, "// BIP122 chain uri and then contract instance name.\n")
+	fmt.Fprintf(&w, "var Deployments = map[string]map[string]common.Address{\n")
+	for _, chain := range chains {
+		instances := make([]string, 0, len(p.Deployments[chain]))
+		for k := range p.Deployments[chain] {
+			instances = append(instances, k)
+		}
+		sort.Strings(instances)
+		fmt.Fprintf(&w, "%q: {\n", chain)
+		for _, k := range instances {
+			fmt.Fprintf(&w, "%q: common.HexToAddress(%q),\n", k, p.Deployments[chain][k].Address)
+		}
+		fmt.Fprintf(&w, "},\n")
+	}
+	fmt.Fprintf(&w, "}\n")
+	return w.String()
+}
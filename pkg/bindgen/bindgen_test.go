@@ -0,0 +1,79 @@
+package bindgen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+const testABI = `[{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf",` +
+	`"outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// ownedABI has a view function without the 'constant' flag, as written by solc 0.5
+const ownedABI = `[{"inputs":[],"name":"owner","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+
+func TestGenerate(t *testing.T) {
+	abis, err := ethcontract.ParseABI(testABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned, err := ethcontract.ParseABI(ownedABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &ethpm.PackageManifest{PackageName: "token"}
+	p.ContractTypes = map[string]*ethcontract.ContractType{
+		"standard-token": {
+			ABI: abis,
+			DeploymentBytecode: &bc.UnlinkedBytecode{
+				Bytecode: "0x6060" + strings.Repeat(strings.Repeat("00", 20)+"6060", 3),
+				LinkReferences: []*liblink.LinkReference{
+					{Offsets: []int{2}, Length: 20, Name: "SafeMathLib"},
+					{Offsets: []int{24}, Length: 20, Name: "Math", Source: "a/Math.sol"},
+					{Offsets: []int{46}, Length: 20, Name: "Math", Source: "b/Math.sol"},
+				},
+			},
+		},
+		"Owned": {ABI: owned},
+	}
+	chain := "blockchain://d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3/block/752820c0ad7abc1200f9ad42c4adc6fbb4bd44b5bed4667990e64565102c1ba6"
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		chain: {"Token": {Address: "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", ContractType: "standard-token"}},
+	}
+
+	code, err := Generate(p, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), "token.go", code, 0); err != nil {
+		t.Fatalf("Generated code does not parse: '%v'", err)
+	}
+	for _, want := range []string{
+		"package token",
+		"type StandardToken struct",
+		"type Owned struct",
+		"const StandardTokenBin = `0x6060__SafeMathLib___________________________6060" +
+			bc.Placeholder("a/Math.sol:Math") + "6060" + bc.Placeholder("b/Math.sol:Math") + "6060`",
+		`"a/Math.sol:Math": "` + bc.Placeholder("a/Math.sol:Math") + `"`,
+		"func LinkStandardTokenBin(libraries map[string]common.Address) (string, error)",
+		"func DeployStandardToken(auth *bind.TransactOpts, backend bind.ContractBackend, bin string)",
+		"range StandardTokenLinkReferences {\n\t\tif strings.Contains(bin, placeholder) {",
+		"common.FromHex(bin)",
+		"func (_Owned *OwnedCaller) Owner(",
+		`"Token": common.HexToAddress("0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0")`,
+		chain,
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("Generated code is missing '%v'", want)
+		}
+	}
+	if strings.Contains(code, "func DeployOwned(") {
+		t.Fatal("Got a deploy function for a contract type without deployment bytecode")
+	}
+}
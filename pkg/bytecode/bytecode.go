@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ethpm/ethpm-go/pkg/ethregexlib"
@@ -31,14 +32,20 @@ import (
 // StandardJSONBC is the bytecode or deployedBytecode object from a compiler's
 // standard JSON output object
 type StandardJSONBC struct {
-	LinkReferences map[string]map[string][]map[string]int `json:"linkReferences,omitempty"`
-	Object         string                                 `json:"object,omitempty"`
+	ImmutableReferences map[string][]map[string]int            `json:"immutableReferences,omitempty"`
+	LinkReferences      map[string]map[string][]map[string]int `json:"linkReferences,omitempty"`
+	Object              string                                 `json:"object,omitempty"`
+	SourceMap           string                                 `json:"sourceMap,omitempty"`
 }
 
-// UnlinkedBytecode A bytecode object for unlinked bytecode.
+// UnlinkedBytecode A bytecode object for unlinked bytecode. Immutable references
+// and source maps are extensions of the v2 spec, written under keys prefixed
+// with "x-". Immutable references are only present in runtime bytecode.
 type UnlinkedBytecode struct {
-	Bytecode       string                   `json:"bytecode,omitempty"`
-	LinkReferences []*liblink.LinkReference `json:"link_references,omitempty"`
+	Bytecode            string                   `json:"bytecode,omitempty"`
+	ImmutableReferences []*ImmutableReference    `json:"x-immutable_references,omitempty"`
+	LinkReferences      []*liblink.LinkReference `json:"link_references,omitempty"`
+	SourceMap           string                   `json:"x-source_map,omitempty"`
 }
 
 // LinkedBytecode A bytecode object for linked bytecode
@@ -69,15 +76,32 @@ func (ub *UnlinkedBytecode) Build(jsonstring string) (err error) {
 	for k := range s.LinkReferences {
 		for z, v := range s.LinkReferences[k] {
 			ub.LinkReferences[contractcount] = &liblink.LinkReference{}
-			ub.LinkReferences[contractcount].Build(z, v)
+			ub.LinkReferences[contractcount].Build(k, z, v)
 			s.Object = addLinkRefZeros(s.Object, ub.LinkReferences[contractcount])
 			contractcount++
 		}
 	}
+	sortLinkReferences(ub.LinkReferences)
 	ub.Bytecode = s.Object
+	ub.ImmutableReferences = buildImmutableReferences(s.ImmutableReferences)
+	ub.SourceMap = s.SourceMap
+	if (contractcount == 0) && strings.Contains(s.Object, "__") {
+		// Placeholders without link references, resolved from their names alone
+		err = ub.BuildFromPlaceholders(s.Object)
+	}
 	return
 }
 
+// sortLinkReferences orders link references by library name, then by source
+func sortLinkReferences(lr []*liblink.LinkReference) {
+	sort.Slice(lr, func(i, j int) bool {
+		if lr[i].Name != lr[j].Name {
+			return lr[i].Name < lr[j].Name
+		}
+		return lr[i].Source < lr[j].Source
+	})
+}
+
 func addLinkRefZeros(bytecode string, lr *liblink.LinkReference) string {
 	l := lr.Length * 2
 	zeros := strings.Repeat("0", l)
@@ -88,6 +112,86 @@ func addLinkRefZeros(bytecode string, lr *liblink.LinkReference) string {
 	return bytecode
 }
 
+// Link takes a mapping of link reference names to hex encoded values, such as
+// the addresses of deployed libraries, and returns the bytecode with every value
+// written into the offsets of its link reference. Values are looked up by the
+// fully qualified "source:Name" of a link reference first, then by its name if
+// no other link reference has the same name with a different source.
+// The returned bytecode is always prefixed with "0x". Every link reference must
+// have a value of matching length.
+func (ub *UnlinkedBytecode) Link(values map[string]string) (linked string, err error) {
+	linked = strings.TrimPrefix(strings.TrimPrefix(ub.Bytecode, "0x"), "0X")
+	qualified := make(map[string]map[string]bool)
+	for _, lr := range ub.LinkReferences {
+		if qualified[lr.Name] == nil {
+			qualified[lr.Name] = make(map[string]bool)
+		}
+		qualified[lr.Name][lr.FullyQualifiedName()] = true
+	}
+	for k, lr := range ub.LinkReferences {
+		v, ok := values[lr.FullyQualifiedName()]
+		if !ok && (len(qualified[lr.Name]) == 1) {
+			v, ok = values[lr.Name]
+		}
+		if !ok {
+			err = fmt.Errorf("No value provided for link_reference '%v' at position '%v'", lr.FullyQualifiedName(), k)
+			return
+		}
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "0x"), "0X")
+		if len(v) != lr.Length*2 {
+			err = fmt.Errorf("Value '%v' for link_reference '%v' is %v bytes, expected %v bytes",
+				v, lr.FullyQualifiedName(), len(v)/2, lr.Length)
+			return
+		}
+		for _, x := range lr.Offsets {
+			spot := x * 2
+			if spot+len(v) > len(linked) {
+				err = fmt.Errorf("Offset '%v' for link_reference '%v' is out of bounds for the bytecode", x, lr.FullyQualifiedName())
+				return
+			}
+			linked = linked[:spot] + v + linked[spot+len(v):]
+		}
+	}
+	linked = "0x" + linked
+	return
+}
+
+// CompareUnlinked takes another unlinked bytecode, such as one produced by
+// recompiling the sources of this one, and the modes to try, all of MatchModes
+// if none are given. The link references of both are zeroed in both bytecodes
+// before they are compared, so values written into either do not affect the
+// result. It returns the first mode under which they are equal, or an empty
+// mode if they do not match.
+func (ub *UnlinkedBytecode) CompareUnlinked(other *UnlinkedBytecode, modes ...MatchMode) (match MatchMode, err error) {
+	refs := append(append([]*liblink.LinkReference{}, ub.LinkReferences...), other.LinkReferences...)
+	a, err := maskLinkReferences(ub.Bytecode, refs)
+	if err != nil {
+		return
+	}
+	b, err := maskLinkReferences(other.Bytecode, refs)
+	if err != nil {
+		return
+	}
+	match = Compare(a, b, modes...)
+	return
+}
+
+// maskLinkReferences returns the bytecode, without a 0x prefix, with the
+// offsets of every link reference zeroed
+func maskLinkReferences(bytecode string, refs []*liblink.LinkReference) (masked string, err error) {
+	masked = strings.TrimPrefix(strings.TrimPrefix(bytecode, "0x"), "0X")
+	for _, lr := range refs {
+		for _, o := range lr.Offsets {
+			if (o+lr.Length)*2 > len(masked) {
+				err = fmt.Errorf("Offset '%v' of link reference '%v' is out of bounds for the bytecode", o, lr.FullyQualifiedName())
+				return
+			}
+			masked = masked[:o*2] + strings.Repeat("0", lr.Length*2) + masked[(o+lr.Length)*2:]
+		}
+	}
+	return
+}
+
 // Validate with UnlinkedBytecode ensures the UnlinkedBytecode object conforms to the standard
 // described here https://ethpm.github.io/ethpm-spec/package-spec.html#bytecode
 func (ub *UnlinkedBytecode) Validate() (err error) {
@@ -101,6 +205,10 @@ func (ub *UnlinkedBytecode) Validate() (err error) {
 	}
 	if retErr := checkLinkReferences(ub.Bytecode, ub.LinkReferences); retErr != nil {
 		err = retErr
+		return
+	}
+	if retErr := checkImmutableReferences(ub.Bytecode, ub.ImmutableReferences); retErr != nil {
+		err = retErr
 	}
 	return
 }
@@ -14,6 +14,45 @@ func TestSetVersion(t *testing.T) {
 	}
 }
 
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"solc, the solidity compiler commandline interface\nVersion: 0.4.24+commit.e67f0147.Linux.g++\n", "0.4.24+commit.e67f0147"},
+		{"Version: 0.5.0-nightly.2018.10.9+commit.3c9b2d5b.Darwin.appleclang\n", "0.5.0-nightly.2018.10.9+commit.3c9b2d5b"},
+		{"Version: 0.4.25\n", "0.4.25"},
+		{"0.1.0b4\n", "0.1.0b4"},
+		{"0.3.7+commit.6020b8bb\n", "0.3.7+commit.6020b8bb"},
+	}
+	for _, v := range tests {
+		got, err := ParseVersion(v.output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.want {
+			t.Fatalf("Got '%v', expected '%v'", got, v.want)
+		}
+	}
+	if _, err := ParseVersion("solc: command not found"); err == nil {
+		t.Fatal("Got <nil>, expected an error for output without a version")
+	}
+}
+
+func TestCompilerName(t *testing.T) {
+	tests := map[string]string{
+		"solc":                            "solc",
+		"/root/.ethpm/solc-0.4.24+commit": "solc",
+		"vyper":                           "vyper",
+		"/usr/local/bin/vyper-0.1.0b4":    "vyper",
+	}
+	for k, v := range tests {
+		if got := CompilerName(k); got != v {
+			t.Fatalf("Got '%v', expected '%v'", got, v)
+		}
+	}
+}
+
 func TestSetSettingsFromJSON(t *testing.T) {
 	c := &CompilerInformation{}
 
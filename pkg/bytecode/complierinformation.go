@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/ethpm/ethpm-go/pkg/ethregexlib"
@@ -18,19 +20,29 @@ type CompilerInformation struct {
 	Version  string      `json:"version"`
 }
 
-// Build takes the compiler name, such as "solc", and the 'settings' object from the
-// standard input json and builds the CompilerInformation object
+// Build takes the compiler name or path, such as "solc", and the 'settings' object
+// from the standard input json and builds the CompilerInformation object. The
+// name recorded is the one returned by CompilerName.
 func (c *CompilerInformation) Build(compiler string, settingsjsonstring string) (err error) {
+	c.Name = CompilerName(compiler)
 	if err = c.SetVersion(compiler); err != nil {
 		err = fmt.Errorf("Error building compiler information: '%v'", err)
 	} else if err = c.SetSettingsFromJSON(settingsjsonstring); err != nil {
 		err = fmt.Errorf("Error building compiler information: '%v'", err)
-	} else {
-		c.Name = compiler
 	}
 	return
 }
 
+// CompilerName takes the name or path of an installed compiler and returns the
+// name recorded for it, "vyper" for any executable whose name starts with
+// "vyper" and "solc" for everything else
+func CompilerName(compiler string) string {
+	if strings.HasPrefix(filepath.Base(compiler), "vyper") {
+		return "vyper"
+	}
+	return "solc"
+}
+
 // SetName sets the name of the compiler, such as "solc"
 func (c *CompilerInformation) SetName(t string) {
 	c.Name = t
@@ -51,15 +63,15 @@ func (c *CompilerInformation) SetSettingsFromJSON(jsonstring string) (err error)
 }
 
 // SetVersion takes the name of the compiler and sets the version. The compiler
-// must be installed on your system. Currently only tested against "solc --version"
-// output
+// must be installed on your system. Tested against "solc --version" and
+// "vyper --version" output
 func (c *CompilerInformation) SetVersion(t string) (err error) {
 	c.Version, err = GetVersion(t)
 	return
 }
 
 // GetVersion takes the name of the compiler and returns the version being used.
-// Currently only tested against "solc --version" output
+// Tested against "solc --version" and "vyper --version" output
 func GetVersion(t string) (version string, err error) {
 	execlocation, err := exec.LookPath(t)
 	if err != nil {
@@ -75,8 +87,27 @@ func GetVersion(t string) (version string, err error) {
 		return
 	}
 
-	versionarray := strings.Split(strings.Split(string(execOut), "Version: ")[1], ".")
-	version = strings.Trim(strings.Replace(fmt.Sprint(versionarray[:len(versionarray)-2]), " ", ".", -1), "[]")
+	version, err = ParseVersion(string(execOut))
+	return
+}
+
+var (
+	versionRegex     = regexp.MustCompile(`Version:\s*(\d+\.\d+\.\d+(?:-[0-9A-Za-z.]+)?(?:\+commit\.[0-9a-f]+)?)`)
+	bareVersionRegex = regexp.MustCompile(`^\s*v?(\d+\.\d+\.\d+[0-9A-Za-z.-]*(?:\+commit\.[0-9a-f]+)?)`)
+)
+
+// ParseVersion takes the output of "solc --version" or "vyper --version" and
+// returns the version without the platform suffix, such as "0.4.24+commit.e67f0147"
+func ParseVersion(output string) (version string, err error) {
+	m := versionRegex.FindStringSubmatch(output)
+	if m == nil {
+		m = bareVersionRegex.FindStringSubmatch(output)
+	}
+	if m == nil {
+		err = fmt.Errorf("Could not find a version in compiler output: '%v'", output)
+		return
+	}
+	version = m[1]
 	return
 }
 
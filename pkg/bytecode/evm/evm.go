@@ -0,0 +1,252 @@
+/*
+The MIT License (MIT)
+https://github.com/ethpm/ethpm-go/blob/master/LICENSE
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+/*
+Package evm disassembles the bytecode of package manifests and provides static
+analysis helpers for auditing it, such as extracting the function dispatch table,
+finding dangerous opcodes and separating constructor code from runtime code.
+*/
+package evm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+// Instruction is a single disassembled instruction. Push data overlapping a
+// link reference names the reference in LinkReference, fully qualified when its
+// source is known. The metadata appended by the compiler is not disassembled
+// and is returned as a single instruction named "METADATA" holding the raw
+// bytes in Data.
+type Instruction struct {
+	Data          []byte
+	LinkReference string
+	Metadata      bool
+	Name          string
+	Offset        int
+	Op            byte
+}
+
+// DispatchEntry is a function selector of the dispatch table along with the
+// offset of the code it jumps to, or -1 if the destination is not a constant
+type DispatchEntry struct {
+	Destination int
+	Selector    string
+}
+
+// Finding is an opcode worth reviewing found at an offset of the code
+type Finding struct {
+	Name   string
+	Offset int
+}
+
+// DangerousOpcodes are the opcodes reported by Findings
+var DangerousOpcodes = []byte{SELFDESTRUCT, DELEGATECALL, CREATE2}
+
+// String returns the instruction as "offset: NAME 0xdata"
+func (i *Instruction) String() string {
+	s := fmt.Sprintf("%04x: %v", i.Offset, i.Name)
+	if len(i.Data) > 0 {
+		s += " 0x" + hex.EncodeToString(i.Data)
+	}
+	if i.LinkReference != "" {
+		s += " <" + i.LinkReference + ">"
+	}
+	return s
+}
+
+// Disassemble takes hex encoded bytecode and the link references of it, which
+// can be nil, and returns its instructions. Invalid bytes are returned as
+// instructions named "INVALID" and push data cut short by the end of the code
+// is returned as far as it goes.
+func Disassemble(code string, linkreferences []*liblink.LinkReference) (instructions []*Instruction, err error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(code, "0x"), "0X"))
+	if err != nil {
+		err = fmt.Errorf("Invalid bytecode: '%v'", err)
+		return
+	}
+	end := len(b)
+	if _, md, retErr := bc.SplitMetadata(code); retErr == nil {
+		end -= md.Length
+	}
+	links := make([]string, len(b))
+	for _, lr := range linkreferences {
+		for _, o := range lr.Offsets {
+			for i := o; (i < o+lr.Length) && (i < len(b)); i++ {
+				links[i] = lr.FullyQualifiedName()
+			}
+		}
+	}
+	for pc := 0; pc < end; pc++ {
+		in := &Instruction{Offset: pc, Op: b[pc], Name: "INVALID"}
+		if op, ok := Opcodes[b[pc]]; ok {
+			in.Name = op.Name
+			if op.Push > 0 {
+				stop := pc + 1 + op.Push
+				if stop > end {
+					stop = end
+				}
+				in.Data = b[pc+1 : stop]
+				for i := pc + 1; i < stop; i++ {
+					if links[i] != "" {
+						in.LinkReference = links[i]
+						break
+					}
+				}
+				pc = stop - 1
+			}
+		}
+		instructions = append(instructions, in)
+	}
+	if end < len(b) {
+		instructions = append(instructions, &Instruction{Data: b[end:], Metadata: true, Name: "METADATA", Offset: end})
+	}
+	return
+}
+
+// DisassembleUnlinked disassembles unlinked bytecode, marking its link references
+func DisassembleUnlinked(ub *bc.UnlinkedBytecode) ([]*Instruction, error) {
+	return Disassemble(ub.Bytecode, ub.LinkReferences)
+}
+
+// DisassembleLinked disassembles linked bytecode, marking its link references
+func DisassembleLinked(lb *bc.LinkedBytecode) ([]*Instruction, error) {
+	return Disassemble(lb.Bytecode, lb.LinkReferences)
+}
+
+// Dispatch returns the function selectors compared against in the dispatcher,
+// found as a PUSH4 followed by an EQ and a conditional jump, sorted by selector
+func Dispatch(instructions []*Instruction) (entries []*DispatchEntry) {
+	seen := make(map[string]bool)
+	for i, in := range instructions {
+		if (in.Op != PUSH4) || (len(in.Data) != 4) {
+			continue
+		}
+		// The selector is compared right away or after copying the calldata word
+		j := i + 1
+		if (j < len(instructions)) && isDupOrSwap(instructions[j].Op) {
+			j++
+		}
+		if (j >= len(instructions)) || (instructions[j].Op != EQ) {
+			continue
+		}
+		entry := &DispatchEntry{Destination: -1, Selector: "0x" + hex.EncodeToString(in.Data)}
+		if (j+2 < len(instructions)) && (instructions[j+2].Op == JUMPI) && isPush(instructions[j+1].Op) {
+			entry.Destination = int(new(big.Int).SetBytes(instructions[j+1].Data).Int64())
+		} else if (j+1 >= len(instructions)) || (instructions[j+1].Op != JUMPI) {
+			continue
+		}
+		if !seen[entry.Selector] {
+			seen[entry.Selector] = true
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Selector < entries[j].Selector
+	})
+	return
+}
+
+// Findings returns the offset of every occurrence of DangerousOpcodes
+func Findings(instructions []*Instruction) (findings []*Finding) {
+	for _, in := range instructions {
+		if in.Metadata {
+			continue
+		}
+		for _, op := range DangerousOpcodes {
+			if in.Op == op {
+				findings = append(findings, &Finding{Name: in.Name, Offset: in.Offset})
+			}
+		}
+	}
+	return
+}
+
+// SplitConstructor takes hex encoded deployment bytecode and returns the
+// constructor code and the runtime code it returns, both hex encoded without a
+// 0x prefix. The runtime code is found through the first CODECOPY whose offset
+// and size are constants, following the stack from the start of the code.
+func SplitConstructor(code string) (constructor string, runtime string, err error) {
+	instructions, err := Disassemble(code, nil)
+	if err != nil {
+		return
+	}
+	b, _ := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(code, "0x"), "0X"))
+	// Each stack item is a known constant or nil
+	var stack []*big.Int
+	pop := func() (v *big.Int) {
+		if len(stack) > 0 {
+			v = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		}
+		return
+	}
+	for _, in := range instructions {
+		op, ok := Opcodes[in.Op]
+		if !ok || in.Metadata {
+			break
+		}
+		switch {
+		case isPush(in.Op):
+			stack = append(stack, new(big.Int).SetBytes(in.Data))
+		case (in.Op >= DUP1) && (in.Op <= DUP16):
+			n := int(in.Op-DUP1) + 1
+			var v *big.Int
+			if n <= len(stack) {
+				v = stack[len(stack)-n]
+			}
+			stack = append(stack, v)
+		case (in.Op >= SWAP1) && (in.Op <= SWAP16):
+			n := int(in.Op-SWAP1) + 1
+			if n < len(stack) {
+				top := len(stack) - 1
+				stack[top], stack[top-n] = stack[top-n], stack[top]
+			}
+		case in.Op == CODECOPY:
+			pop()
+			offset, size := pop(), pop()
+			if (offset == nil) || (size == nil) || !offset.IsInt64() || !size.IsInt64() {
+				continue
+			}
+			o, s := int(offset.Int64()), int(size.Int64())
+			if (o > 0) && (s > 0) && (o+s <= len(b)) {
+				constructor = hex.EncodeToString(b[:o])
+				runtime = hex.EncodeToString(b[o : o+s])
+				return
+			}
+		default:
+			for i := 0; i < op.Pops; i++ {
+				pop()
+			}
+			for i := 0; i < op.Pushes; i++ {
+				stack = append(stack, nil)
+			}
+		}
+	}
+	err = fmt.Errorf("Could not find the runtime code in the deployment bytecode")
+	return
+}
+
+func isPush(op byte) bool {
+	return (op == PUSH0) || ((op >= PUSH1) && (op <= PUSH32))
+}
+
+func isDupOrSwap(op byte) bool {
+	return (op >= DUP1) && (op <= SWAP16)
+}
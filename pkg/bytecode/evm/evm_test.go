@@ -0,0 +1,90 @@
+package evm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+const (
+	testRuntime = "600035" + "60e01c" + "80" + "63a9059cbb" + "14" + "601e" + "57" + "80" + "6370a08231" + "14" + "6020" + "57" +
+		"600080fd" + "5b00" + "5b6000ff"
+	testMetadata = "a2646970667358221220" + "abababababababababababababababababababababababababababababababab" + "64736f6c63430008110033"
+)
+
+func TestDisassemble(t *testing.T) {
+	ub := &bc.UnlinkedBytecode{
+		Bytecode:       "0x73" + strings.Repeat("00", 20) + "f4" + testMetadata,
+		LinkReferences: []*liblink.LinkReference{{Offsets: []int{1}, Length: 20, Name: "SafeMathLib"}},
+	}
+	instructions, err := DisassembleUnlinked(ub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instructions) != 3 {
+		t.Fatalf("Got '%v' instructions, expected '%v'", len(instructions), 3)
+	}
+	if got := instructions[0].String(); got != "0000: PUSH20 0x"+strings.Repeat("00", 20)+" <SafeMathLib>" {
+		t.Fatalf("Got '%v', expected a PUSH20 marked with its link reference", got)
+	}
+	if (instructions[1].Name != "DELEGATECALL") || (instructions[1].Offset != 21) {
+		t.Fatalf("Got '%v', expected '%v'", instructions[1], "0015: DELEGATECALL")
+	}
+	if !instructions[2].Metadata || (len(instructions[2].Data) != len(testMetadata)/2) {
+		t.Fatalf("Got '%v', expected the metadata suffix", instructions[2])
+	}
+
+	instructions, err = Disassemble("0x60016001fe0c61ff", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, v := range instructions {
+		names = append(names, v.Name)
+	}
+	if got, want := strings.Join(names, " "), "PUSH1 PUSH1 INVALID INVALID PUSH2"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestDispatchAndFindings(t *testing.T) {
+	instructions, err := Disassemble(testRuntime+testMetadata, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := Dispatch(instructions)
+	if len(entries) != 2 {
+		t.Fatalf("Got '%v' entries, expected '%v'", len(entries), 2)
+	}
+	if (entries[0].Selector != "0x70a08231") || (entries[0].Destination != 0x20) {
+		t.Fatalf("Got '%+v', expected '%v' jumping to '%v'", entries[0], "0x70a08231", 0x20)
+	}
+	if (entries[1].Selector != "0xa9059cbb") || (entries[1].Destination != 0x1e) {
+		t.Fatalf("Got '%+v', expected '%v' jumping to '%v'", entries[1], "0xa9059cbb", 0x1e)
+	}
+	findings := Findings(instructions)
+	if (len(findings) != 1) || (findings[0].Name != "SELFDESTRUCT") || (findings[0].Offset != 35) {
+		t.Fatalf("Got '%+v', expected a single SELFDESTRUCT at 35", findings)
+	}
+}
+
+func TestSplitConstructor(t *testing.T) {
+	runtime := testRuntime + testMetadata
+	constructor := fmt.Sprintf("6080604052348015600f57600080fd5b5060%02x80601d6000396000f3fe", len(runtime)/2)
+	gotConstructor, gotRuntime, err := SplitConstructor("0x" + constructor + runtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotConstructor != constructor {
+		t.Fatalf("Got '%v', expected '%v'", gotConstructor, constructor)
+	}
+	if gotRuntime != runtime {
+		t.Fatalf("Got '%v', expected '%v'", gotRuntime, runtime)
+	}
+	if _, _, err = SplitConstructor("0x" + testRuntime); err == nil {
+		t.Fatalf("Got '%v', expected an error for code without a constructor", err)
+	}
+}
@@ -0,0 +1,136 @@
+package evm
+
+import "strconv"
+
+// OpCode describes an EVM instruction: its mnemonic, the number of bytes of
+// immediate push data following it, and the number of stack items it takes and
+// leaves
+type OpCode struct {
+	Name   string
+	Pops   int
+	Push   int
+	Pushes int
+}
+
+// Opcodes that are interesting on their own
+const (
+	STOP         byte = 0x00
+	EQ           byte = 0x14
+	CODECOPY     byte = 0x39
+	JUMP         byte = 0x56
+	JUMPI        byte = 0x57
+	JUMPDEST     byte = 0x5b
+	PUSH0        byte = 0x5f
+	PUSH1        byte = 0x60
+	PUSH4        byte = 0x63
+	PUSH32       byte = 0x7f
+	DUP1         byte = 0x80
+	DUP16        byte = 0x8f
+	SWAP1        byte = 0x90
+	SWAP16       byte = 0x9f
+	CREATE2      byte = 0xf5
+	DELEGATECALL byte = 0xf4
+	RETURN       byte = 0xf3
+	INVALID      byte = 0xfe
+	SELFDESTRUCT byte = 0xff
+)
+
+// Opcodes maps every defined opcode byte to its description. Bytes missing from
+// the map are invalid instructions.
+var Opcodes = map[byte]*OpCode{
+	0x00: {Name: "STOP"},
+	0x01: {Name: "ADD", Pops: 2, Pushes: 1},
+	0x02: {Name: "MUL", Pops: 2, Pushes: 1},
+	0x03: {Name: "SUB", Pops: 2, Pushes: 1},
+	0x04: {Name: "DIV", Pops: 2, Pushes: 1},
+	0x05: {Name: "SDIV", Pops: 2, Pushes: 1},
+	0x06: {Name: "MOD", Pops: 2, Pushes: 1},
+	0x07: {Name: "SMOD", Pops: 2, Pushes: 1},
+	0x08: {Name: "ADDMOD", Pops: 3, Pushes: 1},
+	0x09: {Name: "MULMOD", Pops: 3, Pushes: 1},
+	0x0a: {Name: "EXP", Pops: 2, Pushes: 1},
+	0x0b: {Name: "SIGNEXTEND", Pops: 2, Pushes: 1},
+	0x10: {Name: "LT", Pops: 2, Pushes: 1},
+	0x11: {Name: "GT", Pops: 2, Pushes: 1},
+	0x12: {Name: "SLT", Pops: 2, Pushes: 1},
+	0x13: {Name: "SGT", Pops: 2, Pushes: 1},
+	0x14: {Name: "EQ", Pops: 2, Pushes: 1},
+	0x15: {Name: "ISZERO", Pops: 1, Pushes: 1},
+	0x16: {Name: "AND", Pops: 2, Pushes: 1},
+	0x17: {Name: "OR", Pops: 2, Pushes: 1},
+	0x18: {Name: "XOR", Pops: 2, Pushes: 1},
+	0x19: {Name: "NOT", Pops: 1, Pushes: 1},
+	0x1a: {Name: "BYTE", Pops: 2, Pushes: 1},
+	0x1b: {Name: "SHL", Pops: 2, Pushes: 1},
+	0x1c: {Name: "SHR", Pops: 2, Pushes: 1},
+	0x1d: {Name: "SAR", Pops: 2, Pushes: 1},
+	0x20: {Name: "SHA3", Pops: 2, Pushes: 1},
+	0x30: {Name: "ADDRESS", Pushes: 1},
+	0x31: {Name: "BALANCE", Pops: 1, Pushes: 1},
+	0x32: {Name: "ORIGIN", Pushes: 1},
+	0x33: {Name: "CALLER", Pushes: 1},
+	0x34: {Name: "CALLVALUE", Pushes: 1},
+	0x35: {Name: "CALLDATALOAD", Pops: 1, Pushes: 1},
+	0x36: {Name: "CALLDATASIZE", Pushes: 1},
+	0x37: {Name: "CALLDATACOPY", Pops: 3},
+	0x38: {Name: "CODESIZE", Pushes: 1},
+	0x39: {Name: "CODECOPY", Pops: 3},
+	0x3a: {Name: "GASPRICE", Pushes: 1},
+	0x3b: {Name: "EXTCODESIZE", Pops: 1, Pushes: 1},
+	0x3c: {Name: "EXTCODECOPY", Pops: 4},
+	0x3d: {Name: "RETURNDATASIZE", Pushes: 1},
+	0x3e: {Name: "RETURNDATACOPY", Pops: 3},
+	0x3f: {Name: "EXTCODEHASH", Pops: 1, Pushes: 1},
+	0x40: {Name: "BLOCKHASH", Pops: 1, Pushes: 1},
+	0x41: {Name: "COINBASE", Pushes: 1},
+	0x42: {Name: "TIMESTAMP", Pushes: 1},
+	0x43: {Name: "NUMBER", Pushes: 1},
+	0x44: {Name: "DIFFICULTY", Pushes: 1},
+	0x45: {Name: "GASLIMIT", Pushes: 1},
+	0x46: {Name: "CHAINID", Pushes: 1},
+	0x47: {Name: "SELFBALANCE", Pushes: 1},
+	0x48: {Name: "BASEFEE", Pushes: 1},
+	0x49: {Name: "BLOBHASH", Pops: 1, Pushes: 1},
+	0x4a: {Name: "BLOBBASEFEE", Pushes: 1},
+	0x50: {Name: "POP", Pops: 1},
+	0x51: {Name: "MLOAD", Pops: 1, Pushes: 1},
+	0x52: {Name: "MSTORE", Pops: 2},
+	0x53: {Name: "MSTORE8", Pops: 2},
+	0x54: {Name: "SLOAD", Pops: 1, Pushes: 1},
+	0x55: {Name: "SSTORE", Pops: 2},
+	0x56: {Name: "JUMP", Pops: 1},
+	0x57: {Name: "JUMPI", Pops: 2},
+	0x58: {Name: "PC", Pushes: 1},
+	0x59: {Name: "MSIZE", Pushes: 1},
+	0x5a: {Name: "GAS", Pushes: 1},
+	0x5b: {Name: "JUMPDEST"},
+	0x5c: {Name: "TLOAD", Pops: 1, Pushes: 1},
+	0x5d: {Name: "TSTORE", Pops: 2},
+	0x5e: {Name: "MCOPY", Pops: 3},
+	0x5f: {Name: "PUSH0", Pushes: 1},
+	0xa0: {Name: "LOG0", Pops: 2},
+	0xa1: {Name: "LOG1", Pops: 3},
+	0xa2: {Name: "LOG2", Pops: 4},
+	0xa3: {Name: "LOG3", Pops: 5},
+	0xa4: {Name: "LOG4", Pops: 6},
+	0xf0: {Name: "CREATE", Pops: 3, Pushes: 1},
+	0xf1: {Name: "CALL", Pops: 7, Pushes: 1},
+	0xf2: {Name: "CALLCODE", Pops: 7, Pushes: 1},
+	0xf3: {Name: "RETURN", Pops: 2},
+	0xf4: {Name: "DELEGATECALL", Pops: 6, Pushes: 1},
+	0xf5: {Name: "CREATE2", Pops: 4, Pushes: 1},
+	0xfa: {Name: "STATICCALL", Pops: 6, Pushes: 1},
+	0xfd: {Name: "REVERT", Pops: 2},
+	0xfe: {Name: "INVALID"},
+	0xff: {Name: "SELFDESTRUCT", Pops: 1},
+}
+
+func init() {
+	for i := 1; i <= 32; i++ {
+		Opcodes[PUSH1+byte(i-1)] = &OpCode{Name: "PUSH" + strconv.Itoa(i), Push: i, Pushes: 1}
+	}
+	for i := 1; i <= 16; i++ {
+		Opcodes[DUP1+byte(i-1)] = &OpCode{Name: "DUP" + strconv.Itoa(i), Pops: i, Pushes: i + 1}
+		Opcodes[SWAP1+byte(i-1)] = &OpCode{Name: "SWAP" + strconv.Itoa(i), Pops: i + 1, Pushes: i + 1}
+	}
+}
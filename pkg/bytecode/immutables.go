@@ -0,0 +1,114 @@
+package bytecode
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImmutableReference is a location in runtime bytecode which the constructor
+// fills with the value of an immutable variable. The name is the id of the
+// variable's declaration in the compiler's AST.
+type ImmutableReference struct {
+	Length  int    `json:"length"`
+	Name    string `json:"name"`
+	Offsets []int  `json:"offsets"`
+}
+
+// buildImmutableReferences converts the immutableReferences of the compiler's
+// standard json output into ImmutableReferences sorted by AST id
+func buildImmutableReferences(refs map[string][]map[string]int) (irs []*ImmutableReference) {
+	for k, v := range refs {
+		ir := &ImmutableReference{Name: k}
+		for _, r := range v {
+			ir.Length = r["length"]
+			ir.Offsets = append(ir.Offsets, r["start"])
+		}
+		sort.Ints(ir.Offsets)
+		irs = append(irs, ir)
+	}
+	sort.Slice(irs, func(i, j int) bool {
+		a, aErr := strconv.Atoi(irs[i].Name)
+		b, bErr := strconv.Atoi(irs[j].Name)
+		if (aErr == nil) && (bErr == nil) {
+			return a < b
+		}
+		return irs[i].Name < irs[j].Name
+	})
+	return
+}
+
+// MaskImmutables takes hex encoded deployed runtime code and returns it with
+// the immutable references of this bytecode zeroed out, without a 0x prefix,
+// along with the 0x prefixed value found for each reference. Every offset of a
+// reference must hold the same value.
+func (ub *UnlinkedBytecode) MaskImmutables(code string) (masked string, values map[string]string, err error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(code, "0x"), "0X"))
+	if err != nil {
+		err = fmt.Errorf("Invalid bytecode: '%v'", err)
+		return
+	}
+	values = make(map[string]string)
+	for _, ir := range ub.ImmutableReferences {
+		for _, o := range ir.Offsets {
+			if (o < 0) || (ir.Length < 0) || (o+ir.Length > len(b)) {
+				err = fmt.Errorf("Offset '%v' of immutable reference '%v' is out of bounds for the bytecode", o, ir.Name)
+				return
+			}
+			v := "0x" + hex.EncodeToString(b[o:o+ir.Length])
+			if prev, ok := values[ir.Name]; ok && (prev != v) {
+				err = fmt.Errorf("Immutable reference '%v' holds '%v' and '%v'", ir.Name, prev, v)
+				return
+			}
+			values[ir.Name] = v
+			for i := o; i < o+ir.Length; i++ {
+				b[i] = 0
+			}
+		}
+	}
+	masked = hex.EncodeToString(b)
+	return
+}
+
+// VerifyDeployed takes the hex encoded runtime code of a deployed contract and
+// compares it with this runtime bytecode, ignoring the values of immutables and
+// of linked libraries. It returns the way the code matched, exactly or ignoring
+// the metadata, or an empty mode if it did not, along with the values of the
+// immutables.
+func (ub *UnlinkedBytecode) VerifyDeployed(code string) (match MatchMode, values map[string]string, err error) {
+	masked, values, err := ub.MaskImmutables(code)
+	if err != nil {
+		return
+	}
+	for _, lr := range ub.LinkReferences {
+		for _, o := range lr.Offsets {
+			if (o < 0) || (lr.Length < 0) || ((o+lr.Length)*2 > len(masked)) {
+				err = fmt.Errorf("Offset '%v' of link reference '%v' is out of bounds for the bytecode", o, lr.Name)
+				return
+			}
+			masked = masked[:o*2] + strings.Repeat("0", lr.Length*2) + masked[(o+lr.Length)*2:]
+		}
+	}
+	match = Compare(masked, ub.Bytecode, MatchExact, MatchIgnoreMetadata)
+	return
+}
+
+// checkImmutableReferences makes sure every immutable reference fits the bytecode
+func checkImmutableReferences(bc string, irs []*ImmutableReference) (err error) {
+	length := (len(strings.TrimPrefix(bc, "0x"))) / 2
+	for k, ir := range irs {
+		if ir.Length <= 0 {
+			err = fmt.Errorf("immutable_reference at position '%v' has invalid length '%v'", k, ir.Length)
+			return
+		}
+		for _, o := range ir.Offsets {
+			if (o < 0) || (o+ir.Length > length) {
+				err = fmt.Errorf("immutable_reference at position '%v' has offset '%v' out of bounds for the bytecode", k, o)
+				return
+			}
+		}
+	}
+	return
+}
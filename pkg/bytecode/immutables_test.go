@@ -0,0 +1,79 @@
+package bytecode
+
+import (
+	"strings"
+	"testing"
+
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+// immutableRuntime returns the value of an immutable, PUSH32 <value> followed by
+// code storing it in memory and returning it
+const immutableRuntime = "7f" + "0000000000000000000000000000000000000000000000000000000000000000" + "60005260206000f3"
+
+func TestBuildImmutableReferences(t *testing.T) {
+	ub := UnlinkedBytecode{}
+	err := ub.Build(`{"immutableReferences":{"12":[{"length":32,"start":1}],"3":[{"length":32,"start":70},{"length":32,"start":40}]},` +
+		`"object":"` + immutableRuntime + `"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ub.ImmutableReferences) != 2 {
+		t.Fatalf("Got '%v' immutable references, expected '%v'", len(ub.ImmutableReferences), 2)
+	}
+	got := ub.ImmutableReferences[0]
+	if (got.Name != "3") || (got.Length != 32) || (got.Offsets[0] != 40) || (got.Offsets[1] != 70) {
+		t.Fatalf("Got '%+v', expected reference '3' at offsets 40 and 70", got)
+	}
+	if err = ub.Validate(); err == nil {
+		t.Fatalf("Got '%v', expected out of bounds immutable references to be rejected", err)
+	}
+}
+
+func TestVerifyDeployed(t *testing.T) {
+	ub := UnlinkedBytecode{
+		Bytecode:            "0x" + immutableRuntime + "73" + strings.Repeat("00", 20) + "00",
+		ImmutableReferences: []*ImmutableReference{{Length: 32, Name: "7", Offsets: []int{1}}},
+		LinkReferences:      []*liblink.LinkReference{{Length: 20, Name: "SafeMathLib", Offsets: []int{42}}},
+	}
+	if err := ub.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	value := strings.Repeat("00", 31) + "2a"
+	deployed := "7f" + value + "60005260206000f3" + "73" + strings.Repeat("11", 20) + "00"
+	match, values, err := ub.VerifyDeployed(deployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match != MatchExact {
+		t.Fatalf("Got '%v', expected '%v'", match, MatchExact)
+	}
+	if values["7"] != "0x"+value {
+		t.Fatalf("Got '%v', expected '%v'", values["7"], "0x"+value)
+	}
+	if match, _, _ = ub.VerifyDeployed(strings.Replace(deployed, "6000f3", "6001f3", 1)); match != "" {
+		t.Fatalf("Got '%v', expected no match", match)
+	}
+	ub.Bytecode += ipfsMetadata
+	if match, _, _ = ub.VerifyDeployed(deployed + withOtherSolcMetadata); match != MatchIgnoreMetadata {
+		t.Fatalf("Got '%v', expected '%v'", match, MatchIgnoreMetadata)
+	}
+	if match, _, _ = ub.VerifyDeployed(strings.Replace(deployed, "6000f3", "6001f3", 1) + ipfsMetadata); match != "" {
+		t.Fatalf("Got '%v', expected no match for different code with the same metadata", match)
+	}
+	ub.Bytecode = strings.TrimSuffix(ub.Bytecode, ipfsMetadata)
+	ub.ImmutableReferences[0].Offsets = []int{1, 42}
+	ub.ImmutableReferences[0].Length = 20
+	if _, _, err = ub.VerifyDeployed(deployed); err == nil {
+		t.Fatalf("Got '%v', expected an error for differing immutable values", err)
+	}
+	ub.ImmutableReferences[0].Offsets = []int{-1}
+	if _, _, err = ub.VerifyDeployed(deployed); err == nil {
+		t.Fatalf("Got '%v', expected an error for a negative immutable offset", err)
+	}
+	ub.ImmutableReferences = nil
+	ub.LinkReferences[0].Offsets = []int{-1}
+	if _, _, err = ub.VerifyDeployed(deployed); err == nil {
+		t.Fatalf("Got '%v', expected an error for a negative link offset", err)
+	}
+}
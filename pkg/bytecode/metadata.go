@@ -0,0 +1,271 @@
+package bytecode
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MatchMode is the way two bytecodes were found to be equal
+type MatchMode string
+
+// Modes for comparing bytecode, from the strictest to the loosest
+const (
+	// MatchExact requires the bytecode to be identical
+	MatchExact MatchMode = "exact"
+	// MatchMetadataHash requires the bytecode to be identical once the trailing
+	// metadata is removed and the metadata hashes embedded in it to be identical,
+	// which commits to the sources and the compiler settings
+	MatchMetadataHash MatchMode = "metadata-hash-only"
+	// MatchIgnoreMetadata requires the bytecode to be identical once the
+	// trailing metadata is removed
+	MatchIgnoreMetadata MatchMode = "ignore-metadata"
+)
+
+// MatchModes lists every mode in the order Compare tries them by default
+var MatchModes = []MatchMode{MatchExact, MatchMetadataHash, MatchIgnoreMetadata}
+
+// Metadata is the cbor encoded metadata solc appends to bytecode. Hashes are
+// hex encoded without a 0x prefix, and the solc version is either the three
+// bytes of a release, such as "0.5.17", or the full version string of older
+// and prerelease compilers.
+type Metadata struct {
+	Bzzr0        string
+	Bzzr1        string
+	Experimental bool
+	IPFS         string
+	Length       int
+	Solc         string
+}
+
+// ParseMetadata takes hex encoded bytecode and returns the metadata encoded
+// at its end
+func ParseMetadata(bytecode string) (md *Metadata, err error) {
+	_, md, err = SplitMetadata(bytecode)
+	return
+}
+
+// SplitMetadata takes hex encoded bytecode and returns the code without its
+// metadata, hex encoded without a 0x prefix, along with the decoded metadata.
+// The metadata is the cbor encoded map followed by its length as two bytes.
+func SplitMetadata(bytecode string) (code string, md *Metadata, err error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(bytecode, "0x"), "0X"))
+	if err != nil {
+		err = fmt.Errorf("Invalid bytecode: '%v'", err)
+		return
+	}
+	if len(b) < 2 {
+		err = errors.New("Bytecode too short to contain metadata")
+		return
+	}
+	length := int(binary.BigEndian.Uint16(b[len(b)-2:]))
+	if (length == 0) || (length > len(b)-2) {
+		err = fmt.Errorf("Invalid metadata length '%v'", length)
+		return
+	}
+	start := len(b) - 2 - length
+	d := &cborDecoder{data: b[start : len(b)-2]}
+	fields, err := d.readMap()
+	if err != nil {
+		err = fmt.Errorf("Invalid metadata: '%v'", err)
+		return
+	}
+	if d.pos != len(d.data) {
+		err = errors.New("Invalid metadata: trailing bytes after the cbor map")
+		return
+	}
+	md = &Metadata{Length: length + 2}
+	for k, v := range fields {
+		switch k {
+		case "bzzr0":
+			md.Bzzr0, err = hexField(k, v)
+		case "bzzr1":
+			md.Bzzr1, err = hexField(k, v)
+		case "ipfs":
+			md.IPFS, err = hexField(k, v)
+		case "experimental":
+			md.Experimental, _ = v.(bool)
+		case "solc":
+			switch s := v.(type) {
+			case []byte:
+				if len(s) != 3 {
+					err = fmt.Errorf("Invalid solc version '%x'", s)
+					break
+				}
+				md.Solc = fmt.Sprintf("%v.%v.%v", s[0], s[1], s[2])
+			case string:
+				md.Solc = s
+			}
+		}
+		if err != nil {
+			md = nil
+			return
+		}
+	}
+	code = hex.EncodeToString(b[:start])
+	return
+}
+
+// Hash returns the metadata hash, ipfs if present and swarm otherwise
+func (md *Metadata) Hash() string {
+	switch {
+	case md.IPFS != "":
+		return md.IPFS
+	case md.Bzzr1 != "":
+		return md.Bzzr1
+	}
+	return md.Bzzr0
+}
+
+// Compare takes two hex encoded bytecodes and the modes to try, all of
+// MatchModes if none are given, and returns the first mode under which they are
+// equal, or an empty mode if they do not match
+func Compare(a string, b string, modes ...MatchMode) (mode MatchMode) {
+	if len(modes) == 0 {
+		modes = MatchModes
+	}
+	a = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(a, "0x"), "0X"))
+	b = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(b, "0x"), "0X"))
+	for _, m := range modes {
+		switch m {
+		case MatchExact:
+			if a == b {
+				return m
+			}
+		case MatchMetadataHash:
+			acode, amd, aErr := SplitMetadata(a)
+			bcode, bmd, bErr := SplitMetadata(b)
+			if (aErr == nil) && (bErr == nil) && (acode == bcode) && (amd.Hash() != "") && (amd.Hash() == bmd.Hash()) {
+				return m
+			}
+		case MatchIgnoreMetadata:
+			acode, _, aErr := SplitMetadata(a)
+			bcode, _, bErr := SplitMetadata(b)
+			if (aErr == nil) && (bErr == nil) && (acode == bcode) {
+				return m
+			}
+		}
+	}
+	return
+}
+
+func hexField(name string, v interface{}) (s string, err error) {
+	b, ok := v.([]byte)
+	if !ok {
+		err = fmt.Errorf("Metadata field '%v' is not a byte string", name)
+		return
+	}
+	s = hex.EncodeToString(b)
+	return
+}
+
+// cborDecoder decodes the subset of cbor used by solc metadata: maps with text
+// keys and unsigned integer, byte string, text string or boolean values
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) readMap() (fields map[string]interface{}, err error) {
+	major, n, err := d.readHead()
+	if err != nil {
+		return
+	}
+	if major != 5 {
+		err = fmt.Errorf("Expected a map, got major type %v", major)
+		return
+	}
+	fields = make(map[string]interface{})
+	for i := uint64(0); i < n; i++ {
+		key, retErr := d.readValue()
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		k, ok := key.(string)
+		if !ok {
+			err = fmt.Errorf("Expected a text key, got '%v'", key)
+			return
+		}
+		if fields[k], err = d.readValue(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (d *cborDecoder) readValue() (v interface{}, err error) {
+	start := d.pos
+	major, n, err := d.readHead()
+	if err != nil {
+		return
+	}
+	switch major {
+	case 0:
+		v = n
+	case 2, 3:
+		if n > uint64(len(d.data)-d.pos) {
+			err = errors.New("String runs past the end of the data")
+			return
+		}
+		b := d.data[d.pos : d.pos+int(n)]
+		d.pos += int(n)
+		if major == 2 {
+			v = b
+		} else {
+			v = string(b)
+		}
+	case 7:
+		switch d.data[start] {
+		case 0xf4:
+			v = false
+		case 0xf5:
+			v = true
+		default:
+			err = fmt.Errorf("Unsupported simple value 0x%x", d.data[start])
+		}
+	default:
+		err = fmt.Errorf("Unsupported major type %v", major)
+	}
+	return
+}
+
+// readHead reads the major type and the argument of the next item
+func (d *cborDecoder) readHead() (major byte, n uint64, err error) {
+	if d.pos >= len(d.data) {
+		err = errors.New("Unexpected end of data")
+		return
+	}
+	major = d.data[d.pos] >> 5
+	info := d.data[d.pos] & 0x1f
+	d.pos++
+	if (info < 24) || (major == 7) {
+		n = uint64(info)
+		return
+	}
+	size := 0
+	switch info {
+	case 24:
+		size = 1
+	case 25:
+		size = 2
+	case 26:
+		size = 4
+	case 27:
+		size = 8
+	default:
+		err = fmt.Errorf("Unsupported additional information %v", info)
+		return
+	}
+	if d.pos+size > len(d.data) {
+		err = errors.New("Unexpected end of data")
+		return
+	}
+	for _, c := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(c)
+	}
+	d.pos += size
+	return
+}
@@ -0,0 +1,65 @@
+package bytecode
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	testHash     = strings.Repeat("ab", 32)
+	ipfsMetadata = "a2646970667358221220" + testHash + "64736f6c63430008110033"
+	bzzrMetadata = "a165627a7a72305820" + testHash + "0029"
+	// withOtherSolcMetadata has the hash of ipfsMetadata with a different solc version
+	withOtherSolcMetadata = "a2646970667358221220" + testHash + "64736f6c63430008120033"
+)
+
+func TestParseMetadata(t *testing.T) {
+	code, md, err := SplitMetadata("0x6080604052" + ipfsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "6080604052" {
+		t.Fatalf("Got '%v', expected '%v'", code, "6080604052")
+	}
+	if (md.IPFS != "1220"+testHash) || (md.Solc != "0.8.17") || (md.Length != len(ipfsMetadata)/2) {
+		t.Fatalf("Got '%+v', expected ipfs hash and solc 0.8.17", md)
+	}
+	if md.Hash() != md.IPFS {
+		t.Fatalf("Got '%v', expected '%v'", md.Hash(), md.IPFS)
+	}
+
+	md, err = ParseMetadata("6080604052" + bzzrMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (md.Bzzr0 != testHash) || (md.Solc != "") {
+		t.Fatalf("Got '%+v', expected bzzr0 hash only", md)
+	}
+
+	if _, err = ParseMetadata("0x6080604052"); err == nil {
+		t.Fatalf("Got '%v', expected an error for bytecode without metadata", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	withOtherHash := "a2646970667358221220" + strings.Repeat("cd", 32) + "64736f6c63430008110033"
+	tests := []struct {
+		a    string
+		b    string
+		want MatchMode
+	}{
+		{"0x6080604052" + ipfsMetadata, "0X6080604052" + strings.ToUpper(ipfsMetadata), MatchExact},
+		{"0x6080604052" + ipfsMetadata, "0x6080604052" + withOtherHash, MatchIgnoreMetadata},
+		{"0x6080604052" + ipfsMetadata, "0x6080604052" + withOtherSolcMetadata, MatchMetadataHash},
+		{"0x6080604052" + ipfsMetadata, "0x6080604053" + ipfsMetadata, ""},
+		{"0x6080604052" + ipfsMetadata, "0x6080604053" + withOtherHash, ""},
+	}
+	for _, v := range tests {
+		if got := Compare(v.a, v.b); got != v.want {
+			t.Fatalf("Got '%v', expected '%v'", got, v.want)
+		}
+	}
+	if got := Compare("0x6080604052"+ipfsMetadata, "0x6080604053"+ipfsMetadata, MatchIgnoreMetadata); got != "" {
+		t.Fatalf("Got '%v', expected no match", got)
+	}
+}
@@ -0,0 +1,142 @@
+package bytecode
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+// placeholderLength is the length in hex characters of a library placeholder,
+// the 20 bytes of the address it is replaced with
+const placeholderLength = 40
+
+var hashPlaceholder = regexp.MustCompile(`^__\$([0-9a-fA-F]{34})\$__$`)
+
+// Placeholder returns the placeholder solc 0.5 and later writes in place of the
+// address of a library given by its fully qualified name, such as
+// "contracts/Math.sol:SafeMath": the first 17 bytes of the keccak256 hash of the
+// name, hex encoded and wrapped in "__$" and "$__"
+func Placeholder(fullyqualifiedname string) string {
+	hw := sha3.NewKeccak256()
+	hw.Write([]byte(fullyqualifiedname))
+	return "__$" + hex.EncodeToString(hw.Sum(nil))[:34] + "$__"
+}
+
+// LegacyPlaceholder returns the placeholder older compilers write in place of the
+// address of a library: its name cut to 36 characters, wrapped in "__" and
+// padded with underscores to 40 characters
+func LegacyPlaceholder(name string) string {
+	if len(name) > placeholderLength-4 {
+		name = name[:placeholderLength-4]
+	}
+	return "__" + name + strings.Repeat("_", placeholderLength-2-len(name))
+}
+
+// BuildFromPlaceholders takes hex encoded bytecode containing library
+// placeholders instead of link references, as written by 'solc --bin' or found
+// in truffle artifacts, and the names of the libraries it may link against,
+// preferably fully qualified such as "contracts/Math.sol:SafeMath". It finds
+// every placeholder, maps it back to a library name and builds the
+// UnlinkedBytecode with the placeholders replaced by zeros, keeping the 0x
+// prefix of the code if it has one. Link references of fully qualified libraries
+// keep their source path. Legacy placeholders of libraries missing
+// from the list are named after the placeholder itself when it was not cut short.
+func (ub *UnlinkedBytecode) BuildFromPlaceholders(code string, libraries ...string) (err error) {
+	prefix := ""
+	if strings.HasPrefix(code, "0x") || strings.HasPrefix(code, "0X") {
+		prefix, code = "0x", code[2:]
+	}
+	references := make(map[string]*liblink.LinkReference)
+	for i := 0; i+1 < len(code); i += 2 {
+		if code[i:i+2] != "__" {
+			continue
+		}
+		if i+placeholderLength > len(code) {
+			err = fmt.Errorf("Placeholder at offset '%v' is cut short by the end of the bytecode", i/2)
+			return
+		}
+		ph := code[i : i+placeholderLength]
+		source, name, retErr := resolvePlaceholder(ph, libraries)
+		if retErr != nil {
+			err = fmt.Errorf("Placeholder '%v' at offset '%v': %v", ph, i/2, retErr)
+			return
+		}
+		lr := &liblink.LinkReference{Length: placeholderLength / 2, Name: name, Source: source}
+		if prev, ok := references[lr.FullyQualifiedName()]; ok {
+			lr = prev
+		} else {
+			references[lr.FullyQualifiedName()] = lr
+		}
+		lr.Offsets = append(lr.Offsets, i/2)
+		code = code[:i] + strings.Repeat("0", placeholderLength) + code[i+placeholderLength:]
+		i += placeholderLength - 2
+	}
+	if _, err = hex.DecodeString(code); err != nil {
+		err = fmt.Errorf("Invalid bytecode: '%v'", err)
+		return
+	}
+	ub.Bytecode = prefix + code
+	ub.LinkReferences = nil
+	for _, lr := range references {
+		ub.LinkReferences = append(ub.LinkReferences, lr)
+	}
+	sortLinkReferences(ub.LinkReferences)
+	return
+}
+
+// resolvePlaceholder returns the source path, if known, and the name of the
+// library which the placeholder stands for
+func resolvePlaceholder(ph string, libraries []string) (source string, name string, err error) {
+	var matches []string
+	if hashPlaceholder.MatchString(ph) {
+		for _, l := range libraries {
+			if strings.EqualFold(Placeholder(l), ph) {
+				matches = append(matches, l)
+			}
+		}
+		if len(matches) == 0 {
+			err = fmt.Errorf("no library in '%v' hashes to it", strings.Join(libraries, ", "))
+			return
+		}
+	} else {
+		for _, l := range libraries {
+			if (LegacyPlaceholder(l) == ph) || (LegacyPlaceholder(bareName(l)) == ph) {
+				matches = append(matches, l)
+			}
+		}
+		if len(matches) == 0 {
+			inner := strings.TrimRight(ph[2:], "_")
+			if len(inner) == placeholderLength-4 {
+				err = fmt.Errorf("library name is cut short and no library in '%v' matches it", strings.Join(libraries, ", "))
+				return
+			}
+			matches = append(matches, inner)
+		}
+	}
+	for _, m := range matches[1:] {
+		if bareName(m) != bareName(matches[0]) {
+			err = fmt.Errorf("matches more than one library: '%v'", strings.Join(matches, ", "))
+			return
+		}
+	}
+	name = bareName(matches[0])
+	if i := strings.LastIndex(matches[0], ":"); i >= 0 {
+		source = matches[0][:i]
+	}
+	for _, m := range matches[1:] {
+		// Legacy placeholders of same-named libraries cannot tell their sources apart
+		if m != matches[0] {
+			source = ""
+		}
+	}
+	return
+}
+
+// bareName returns the library name of a fully qualified name
+func bareName(fullyqualifiedname string) string {
+	return fullyqualifiedname[strings.LastIndex(fullyqualifiedname, ":")+1:]
+}
@@ -0,0 +1,66 @@
+package bytecode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlaceholder(t *testing.T) {
+	if got, want := len(Placeholder("contracts/Math.sol:SafeMath")), 40; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if got, want := LegacyPlaceholder("SafeMathLib"), "__SafeMathLib___________________________"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if got, want := LegacyPlaceholder("./node_modules/ethereum-libraries-token/contracts/TokenLib.sol:TokenLib"),
+		"__./node_modules/ethereum-libraries-to__"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestBuildFromPlaceholders(t *testing.T) {
+	math := "contracts/Math.sol:SafeMath"
+	token := "./node_modules/ethereum-libraries-token/contracts/TokenLib.sol:TokenLib"
+	code := "0x6073" + Placeholder(math) + "f4" + "73" + LegacyPlaceholder(token) + "f4" + "73" + Placeholder(math) + "00"
+	ub := UnlinkedBytecode{}
+	if err := ub.BuildFromPlaceholders(code, math, token); err != nil {
+		t.Fatal(err)
+	}
+	zeros := strings.Repeat("00", 20)
+	if want := "0x6073" + zeros + "f473" + zeros + "f473" + zeros + "00"; ub.Bytecode != want {
+		t.Fatalf("Got '%v', expected '%v'", ub.Bytecode, want)
+	}
+	if len(ub.LinkReferences) != 2 {
+		t.Fatalf("Got '%v' link references, expected '%v'", len(ub.LinkReferences), 2)
+	}
+	lr := ub.LinkReferences[0]
+	if (lr.Name != "SafeMath") || (lr.Length != 20) || (len(lr.Offsets) != 2) || (lr.Offsets[0] != 2) || (lr.Offsets[1] != 46) {
+		t.Fatalf("Got '%+v', expected SafeMath at offsets 2 and 46", lr)
+	}
+	if got, want := lr.FullyQualifiedName(), math; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if lr = ub.LinkReferences[1]; (lr.Name != "TokenLib") || (lr.Offsets[0] != 24) {
+		t.Fatalf("Got '%+v', expected TokenLib at offset 24", lr)
+	}
+	if err := ub.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ub.BuildFromPlaceholders(code, token); err == nil {
+		t.Fatalf("Got '%v', expected an error for an unknown hash placeholder", err)
+	}
+	if err := ub.BuildFromPlaceholders("0x73" + LegacyPlaceholder("Owned") + "00"); err != nil {
+		t.Fatal(err)
+	}
+	if ub.LinkReferences[0].Name != "Owned" {
+		t.Fatalf("Got '%v', expected '%v'", ub.LinkReferences[0].Name, "Owned")
+	}
+
+	if err := ub.Build(`{"object":"73` + LegacyPlaceholder("Owned") + `00"}`); err != nil {
+		t.Fatal(err)
+	}
+	if (len(ub.LinkReferences) != 1) || (ub.Bytecode != "73"+zeros+"00") {
+		t.Fatalf("Got '%v', expected placeholders replaced by a link reference", ub.Bytecode)
+	}
+}
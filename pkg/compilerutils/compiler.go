@@ -0,0 +1,127 @@
+/*
+The MIT License (MIT)
+https://github.com/ethpm/ethpm-go/blob/master/LICENSE
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+/*
+Package compilerutils provides the `Compiler` interface for the compilers used
+to build contract types, along with implementations for solc and vyper. Both are
+driven through their standard json interfaces.
+*/
+package compilerutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// Source languages understood by the standard json interfaces
+const (
+	Solidity = "Solidity"
+	Vyper    = "Vyper"
+)
+
+// Compiler is a contract compiler with a standard json interface
+type Compiler interface {
+	// Name returns the name recorded in the compiler information object
+	Name() string
+	// Language returns the source language the compiler accepts
+	Language() string
+	// Version returns the version of the installed compiler
+	Version() (version string, err error)
+	// CompileStandardJSON takes a standard json input string and returns the
+	// standard json output string
+	CompileStandardJSON(input string) (output string, err error)
+}
+
+// NewCompiler takes the name or path of an installed compiler and the directory
+// it may read sources from, then returns the matching Compiler. Any executable
+// whose name starts with "vyper" is treated as vyper, everything else as solc.
+func NewCompiler(compiler string, projectdir string) (c Compiler, err error) {
+	if compiler == "" {
+		err = fmt.Errorf("No compiler given")
+		return
+	}
+	if bytecode.CompilerName(compiler) == "vyper" {
+		c = &VyperCompiler{Path: compiler}
+		return
+	}
+	c = &SolcCompiler{Path: compiler, AllowPaths: projectdir}
+	return
+}
+
+// SourceLanguage takes a source path and returns the language of the source
+// based on its extension, ".vy" for Vyper and anything else for Solidity
+func SourceLanguage(sourcepath string) string {
+	if path.Ext(filepath.ToSlash(sourcepath)) == ".vy" {
+		return Vyper
+	}
+	return Solidity
+}
+
+// BuildInput takes a compiler, the sources as they appear in a standard json
+// input, and the solc settings, which can be nil, then returns the standard json
+// input for that compiler as a string. Vyper only uses the evm version of the
+// settings and always requests every output used to build a contract type.
+func BuildInput(c Compiler, sources map[string]map[string]string, settings *solcutils.SolcSettings) (input string, err error) {
+	if settings == nil {
+		settings = solcutils.NewSolcSettings(false, 0)
+	}
+	var si interface{}
+	switch c.Language() {
+	case Vyper:
+		vs := &VyperSettings{
+			EVMVersion:      settings.EVMVersion,
+			OutputSelection: map[string][]string{"*": DefaultVyperOutputSelection},
+		}
+		si = &VyperInput{Language: Vyper, Sources: sources, Settings: vs}
+	default:
+		si = &solcutils.StandardInput{Language: Solidity, Sources: sources, Settings: settings}
+	}
+	b, err := json.Marshal(si)
+	if err != nil {
+		err = fmt.Errorf("Error creating standard json input: '%v'", err)
+		return
+	}
+	input = string(b)
+	return
+}
+
+// SolcCompiler runs an installed solc binary
+type SolcCompiler struct {
+	AllowPaths string
+	Path       string
+}
+
+// Name returns "solc"
+func (s *SolcCompiler) Name() string {
+	return "solc"
+}
+
+// Language returns Solidity
+func (s *SolcCompiler) Language() string {
+	return Solidity
+}
+
+// Version returns the version reported by "solc --version"
+func (s *SolcCompiler) Version() (version string, err error) {
+	return bytecode.GetVersion(s.Path)
+}
+
+// CompileStandardJSON runs "solc --standard-json" with the given input
+func (s *SolcCompiler) CompileStandardJSON(input string) (output string, err error) {
+	return solcutils.RunStandardJSON(s.Path, s.AllowPaths, input)
+}
@@ -0,0 +1,81 @@
+package compilerutils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCompiler(t *testing.T) {
+	tests := map[string]string{
+		"solc":                 "solc",
+		"/usr/local/bin/vyper": "vyper",
+		"vyper-0.1.0b4":        "vyper",
+	}
+	for k, v := range tests {
+		c, err := NewCompiler(k, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Name(); got != v {
+			t.Fatalf("Got '%v', expected '%v'", got, v)
+		}
+	}
+	if _, err := NewCompiler("", ""); err == nil {
+		t.Fatal("Got <nil>, expected an error for an empty compiler")
+	}
+}
+
+func TestSourceLanguage(t *testing.T) {
+	if got := SourceLanguage("./contracts/Token.vy"); got != Vyper {
+		t.Fatalf("Got '%v', expected '%v'", got, Vyper)
+	}
+	if got := SourceLanguage("./contracts/Token.sol"); got != Solidity {
+		t.Fatalf("Got '%v', expected '%v'", got, Solidity)
+	}
+}
+
+func TestBuildInput(t *testing.T) {
+	sources := map[string]map[string]string{"Token": {"content": "@public\ndef a():\n    pass\n"}}
+	got, err := BuildInput(&VyperCompiler{Path: "vyper"}, sources, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"language":"Vyper","sources":{"Token":{"content":"@public\ndef a():\n    pass\n"}},` +
+		`"settings":{"outputSelection":{"*":["abi","devdoc","userdoc","evm.bytecode","evm.deployedBytecode","evm.methodIdentifiers"]}}}`
+	if got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestVyperCompiler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vyper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := "#!/bin/sh\nif [ \"$1\" = \"--version\" ]; then echo '0.1.0b4'; exit 0; fi\ncat > /dev/null\necho '{\"contracts\":{}}'\n"
+	bin := filepath.Join(dir, "vyper")
+	if err = ioutil.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCompiler(bin, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, err := c.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "0.1.0b4" {
+		t.Fatalf("Got '%v', expected '0.1.0b4'", version)
+	}
+	output, err := c.CompileStandardJSON(`{"language":"Vyper"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "{\"contracts\":{}}\n" {
+		t.Fatalf("Got '%v', expected '%v'", output, "{\"contracts\":{}}\n")
+	}
+}
@@ -0,0 +1,71 @@
+package compilerutils
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+)
+
+// DefaultVyperOutputSelection is the output requested from vyper for every
+// source. It contains everything needed to build a complete contract type.
+var DefaultVyperOutputSelection = []string{
+	"abi",
+	"devdoc",
+	"userdoc",
+	"evm.bytecode",
+	"evm.deployedBytecode",
+	"evm.methodIdentifiers",
+}
+
+// VyperSettings represents the settings object in a vyper standard json input
+type VyperSettings struct {
+	EVMVersion      string              `json:"evmVersion,omitempty"`
+	OutputSelection map[string][]string `json:"outputSelection,omitempty"`
+}
+
+// VyperInput represents the vyper standard json input object
+type VyperInput struct {
+	Language string                       `json:"language,omitempty"`
+	Sources  map[string]map[string]string `json:"sources,omitempty"`
+	Settings *VyperSettings               `json:"settings,omitempty"`
+}
+
+// VyperCompiler runs an installed vyper binary
+type VyperCompiler struct {
+	Path string
+}
+
+// Name returns "vyper"
+func (v *VyperCompiler) Name() string {
+	return "vyper"
+}
+
+// Language returns Vyper
+func (v *VyperCompiler) Language() string {
+	return Vyper
+}
+
+// Version returns the version reported by "vyper --version"
+func (v *VyperCompiler) Version() (version string, err error) {
+	return bytecode.GetVersion(v.Path)
+}
+
+// CompileStandardJSON runs "vyper --standard-json" with the given input
+func (v *VyperCompiler) CompileStandardJSON(input string) (output string, err error) {
+	execlocation, err := exec.LookPath(v.Path)
+	if err != nil {
+		err = fmt.Errorf("Error getting vyper bin location: '%v'", err)
+		return
+	}
+	execCmd := exec.Command(execlocation, "--standard-json")
+	execCmd.Stdin = strings.NewReader(input)
+	execOut, err := execCmd.Output()
+	if err != nil {
+		err = fmt.Errorf("Error calling exec command: '%v'", err)
+		return
+	}
+	output = string(execOut)
+	return
+}
@@ -0,0 +1,245 @@
+/*
+The MIT License (MIT)
+https://github.com/ethpm/ethpm-go/blob/master/LICENSE
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+/*
+Package docgen renders documentation pages for the contract types of a package
+manifest, combining their natspec with the signatures of their abi, the
+deployments of the package and its meta information.
+*/
+package docgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+	"github.com/ethpm/ethpm-go/pkg/natspec"
+)
+
+// Output formats understood by Generate
+const (
+	HTML     = "html"
+	Markdown = "md"
+)
+
+// PackageDoc is the package information shown on every page
+type PackageDoc struct {
+	Authors     []string
+	Description string
+	License     string
+	Links       [][2]string
+	Name        string
+	Version     string
+}
+
+// ParamDoc is a documented input or output of an abi entry
+type ParamDoc struct {
+	Doc  string
+	Name string
+	Type string
+}
+
+// EntryDoc is a documented function, event or error
+type EntryDoc struct {
+	Custom          [][2]string
+	Details         string
+	ID              string
+	IDLabel         string
+	Inputs          []*ParamDoc
+	Notice          string
+	Outputs         []*ParamDoc
+	Return          string
+	Signature       string
+	StateMutability string
+}
+
+// DeploymentDoc is a deployed instance of a contract type
+type DeploymentDoc struct {
+	Address string
+	Chain   string
+	Name    string
+}
+
+// ContractDoc is everything shown on the page of a contract type
+type ContractDoc struct {
+	Author      string
+	Constructor *EntryDoc
+	Custom      [][2]string
+	Deployments []*DeploymentDoc
+	Details     string
+	Errors      []*EntryDoc
+	Events      []*EntryDoc
+	Functions   []*EntryDoc
+	Name        string
+	Notice      string
+	Package     *PackageDoc
+	Title       string
+}
+
+// Generate takes a package manifest and a format, Markdown or HTML, and returns
+// one page for every contract type keyed by file name, such as "Token.md", along
+// with an index page listing them. A contract type named "index" is an error.
+func Generate(p *ethpm.PackageManifest, format string) (pages map[string]string, err error) {
+	if (format != Markdown) && (format != HTML) {
+		err = fmt.Errorf("Unknown format '%v', expected '%v' or '%v'", format, Markdown, HTML)
+		return
+	}
+	pages = make(map[string]string)
+	docs := Build(p)
+	for _, doc := range docs {
+		// Case-insensitive filesystems would overwrite "Index" as well
+		if strings.EqualFold(doc.Name, "index") {
+			err = fmt.Errorf("Contract type '%v' has the name of the index page", doc.Name)
+			return
+		}
+		if pages[doc.Name+"."+format], err = render(format, "contract", doc); err != nil {
+			return
+		}
+	}
+	pages["index."+format], err = render(format, "index", struct {
+		Contracts []*ContractDoc
+		Package   *PackageDoc
+	}{docs, packageDoc(p)})
+	return
+}
+
+// Build returns the documentation model of every contract type, sorted by name
+func Build(p *ethpm.PackageManifest) (docs []*ContractDoc) {
+	pd := packageDoc(p)
+	names := make([]string, 0, len(p.ContractTypes))
+	for k := range p.ContractTypes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ct := p.ContractTypes[name]
+		doc := &ContractDoc{Name: name, Package: pd}
+		ns := ct.Natspec
+		if ns == nil {
+			ns = &natspec.DocUnion{}
+		}
+		doc.Author = ns.Author
+		doc.Custom = customDoc(ns.Custom)
+		doc.Details = ns.Details
+		doc.Notice = ns.Notice
+		doc.Title = ns.Title
+		for _, a := range ct.ABI {
+			switch a.Type {
+			case "constructor":
+				doc.Constructor = entryDoc(a, ns.Methods["constructor"])
+			case "function", "":
+				m, ok := ns.Methods[a.Signature()]
+				if !ok {
+					// Public state variables are documented by name
+					m = ns.StateVariables[a.Name]
+				}
+				doc.Functions = append(doc.Functions, entryDoc(a, m))
+			case "event":
+				doc.Events = append(doc.Events, entryDoc(a, ns.Events[a.Signature()]))
+			case "error":
+				var m *natspec.Method
+				if errs := ns.Errors[a.Signature()]; len(errs) > 0 {
+					m = errs[0]
+				}
+				doc.Errors = append(doc.Errors, entryDoc(a, m))
+			}
+		}
+		sortEntries(doc.Functions)
+		sortEntries(doc.Events)
+		sortEntries(doc.Errors)
+		doc.Deployments = deploymentDocs(p, name)
+		docs = append(docs, doc)
+	}
+	return
+}
+
+func entryDoc(a *ethcontract.ABIObject, m *natspec.Method) (e *EntryDoc) {
+	e = &EntryDoc{Signature: a.Signature(), StateMutability: a.StateMutability}
+	if e.Signature == "" {
+		e.Signature = a.Type
+	}
+	e.ID, e.IDLabel = a.Selector(), "Selector"
+	if a.Type == "event" {
+		e.ID, e.IDLabel = a.Topic(), "Topic"
+	}
+	if m == nil {
+		m = &natspec.Method{}
+	}
+	e.Custom = customDoc(m.Custom)
+	e.Details = m.Details
+	e.Notice = m.Notice
+	e.Return = m.Return
+	for _, v := range a.Inputs {
+		e.Inputs = append(e.Inputs, &ParamDoc{Doc: m.Params[v.Name], Name: v.Name, Type: v.CanonicalType()})
+	}
+	for i, v := range a.Outputs {
+		// Unnamed return values are documented as _0, _1 and so on
+		doc, ok := m.Returns[v.Name]
+		if !ok {
+			doc = m.Returns[fmt.Sprintf("_%v", i)]
+		}
+		e.Outputs = append(e.Outputs, &ParamDoc{Doc: doc, Name: v.Name, Type: v.CanonicalType()})
+	}
+	return
+}
+
+func customDoc(custom map[string]string) (tags [][2]string) {
+	for _, k := range natspec.CustomTags(custom) {
+		tags = append(tags, [2]string{k, custom[k]})
+	}
+	return
+}
+
+func sortEntries(entries []*EntryDoc) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Signature < entries[j].Signature
+	})
+}
+
+func deploymentDocs(p *ethpm.PackageManifest, contracttype string) (deployments []*DeploymentDoc) {
+	for chain, instances := range p.Deployments {
+		for name, ci := range instances {
+			if ci.ContractType == contracttype {
+				deployments = append(deployments, &DeploymentDoc{Address: ci.Address, Chain: chain, Name: name})
+			}
+		}
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		if deployments[i].Chain != deployments[j].Chain {
+			return deployments[i].Chain < deployments[j].Chain
+		}
+		return deployments[i].Name < deployments[j].Name
+	})
+	return
+}
+
+func packageDoc(p *ethpm.PackageManifest) (pd *PackageDoc) {
+	pd = &PackageDoc{Name: p.PackageName, Version: p.Version}
+	if p.Meta == nil {
+		return
+	}
+	pd.Authors = p.Meta.Authors
+	pd.Description = p.Meta.Description
+	pd.License = p.Meta.License
+	keys := make([]string, 0, len(p.Meta.Links))
+	for k := range p.Meta.Links {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pd.Links = append(pd.Links, [2]string{k, p.Meta.Links[k]})
+	}
+	return
+}
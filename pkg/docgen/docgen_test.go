@@ -0,0 +1,116 @@
+package docgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/ethpm"
+	"github.com/ethpm/ethpm-go/pkg/natspec"
+)
+
+const testABI = `[{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],` +
+	`"name":"transfer","outputs":[{"name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},` +
+	`{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},` +
+	`{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"},` +
+	`{"inputs":[{"name":"needed","type":"uint256"}],"name":"Insufficient","type":"error"}]`
+
+func testManifest(t *testing.T) (p *ethpm.PackageManifest) {
+	abis, err := ethcontract.ParseABI(testABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = &ethpm.PackageManifest{PackageName: "token", Version: "1.0.0"}
+	p.Meta = &ethpm.PackageMeta{
+		Authors: []string{"Piper Merriam <pipermerriam@gmail.com>"},
+		License: "MIT",
+		Links:   map[string]string{"repo": "https://github.com/ethpm/ethpm-spec"},
+	}
+	p.ContractTypes = map[string]*ethcontract.ContractType{
+		"Token": {
+			ABI: abis,
+			Natspec: &natspec.DocUnion{
+				Custom: map[string]string{"security": "audited"},
+				Events: map[string]*natspec.Method{
+					"Transfer(address,address,uint256)": {Notice: "Tokens moved"},
+				},
+				Title: "A <simple> token",
+				Methods: map[string]*natspec.Method{
+					"transfer(address,uint256)": {
+						Details: "Moves tokens",
+						Notice:  "Send tokens",
+						Params:  map[string]string{"_to": "The recipient | receiver"},
+						Returns: map[string]string{"success": "Whether it worked"},
+					},
+				},
+			},
+		},
+	}
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		"blockchain://d4e56740f876aef8/block/752820c0ad7abc12": {
+			"Token": {Address: "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", ContractType: "Token"},
+		},
+	}
+	return
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	pages, err := Generate(testManifest(t), Markdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("Got '%v' pages, expected '%v'", len(pages), 2)
+	}
+	page := pages["Token.md"]
+	for _, want := range []string{
+		"# Token: A <simple> token",
+		"### `transfer(address,uint256)`",
+		"Selector: `0xa9059cbb`",
+		"Moves tokens",
+		"Send tokens",
+		"| success | `bool` | Whether it worked |",
+		"Tokens moved",
+		"**security:** audited",
+		"| _to | `address` | The recipient \\| receiver |",
+		"### `Transfer(address,address,uint256)`",
+		"Topic: `0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef`",
+		"### `Insufficient(uint256)`",
+		"| blockchain://d4e56740f876aef8/block/752820c0ad7abc12 | Token | `0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0` |",
+		"**License:** MIT",
+		"- [repo](https://github.com/ethpm/ethpm-spec)",
+	} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("Got '%v', expected it to contain '%v'", page, want)
+		}
+	}
+	if want := "- [Token](Token.md): A <simple> token"; !strings.Contains(pages["index.md"], want) {
+		t.Fatalf("Got '%v', expected it to contain '%v'", pages["index.md"], want)
+	}
+
+	p := testManifest(t)
+	p.ContractTypes["Index"] = p.ContractTypes["Token"]
+	if _, err = Generate(p, Markdown); err == nil {
+		t.Fatalf("Got '%v', expected an error for a contract type named like the index page", err)
+	}
+}
+
+func TestGenerateHTML(t *testing.T) {
+	pages, err := Generate(testManifest(t), HTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := pages["Token.html"]
+	for _, want := range []string{
+		"<h1>Token: A &lt;simple&gt; token</h1>",
+		"<h3><code>transfer(address,uint256)</code></h3>",
+		"<td>The recipient | receiver</td>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("Got '%v', expected it to contain '%v'", page, want)
+		}
+	}
+	if _, err = Generate(testManifest(t), "pdf"); err == nil {
+		t.Fatalf("Got '%v', expected an error for an unknown format", err)
+	}
+}
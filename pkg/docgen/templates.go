@@ -0,0 +1,176 @@
+package docgen
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// mdEscape escapes the characters which would break a markdown table cell
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+var funcs = map[string]interface{}{
+	"join":     strings.Join,
+	"mdescape": mdEscape,
+}
+
+const markdownTemplates = `
+{{define "meta"}}{{with .Description}}{{.}}
+
+{{end}}{{with .Authors}}**Authors:** {{join . ", "}}
+
+{{end}}{{with .License}}**License:** {{.}}
+
+{{end}}{{range .Links}}- [{{index . 0}}]({{index . 1}})
+{{end}}{{end}}
+
+{{define "params"}}{{if .}}
+| Name | Type | Description |
+| --- | --- | --- |
+{{range .}}| {{.Name}} | ` + "`{{.Type}}`" + ` | {{mdescape .Doc}} |
+{{end}}{{end}}{{end}}
+
+{{define "custom"}}{{if .}}
+{{range .}}**{{index . 0}}:** {{index . 1}}
+
+{{end}}{{end}}{{end}}
+
+{{define "entry"}}### ` + "`{{.Signature}}`" + `
+{{with .ID}}
+{{$.IDLabel}}: ` + "`{{.}}`" + `
+{{end}}{{with .StateMutability}}
+State mutability: ` + "`{{.}}`" + `
+{{end}}{{with .Notice}}
+{{.}}
+{{end}}{{with .Details}}
+{{.}}
+{{end}}{{template "custom" .Custom}}{{template "params" .Inputs}}{{if .Outputs}}
+Returns:
+{{template "params" .Outputs}}{{end}}{{with .Return}}
+{{.}}
+{{end}}
+{{end}}
+
+{{define "contract"}}# {{.Name}}{{with .Title}}: {{.}}{{end}}
+
+Package ` + "`{{.Package.Name}}`" + ` version ` + "`{{.Package.Version}}`" + `
+
+{{with .Author}}**Author:** {{.}}
+
+{{end}}{{with .Notice}}{{.}}
+
+{{end}}{{with .Details}}{{.}}
+
+{{end}}{{range .Custom}}**{{index . 0}}:** {{index . 1}}
+
+{{end}}{{template "meta" .Package}}{{with .Constructor}}
+## Constructor
+
+{{template "entry" .}}{{end}}{{with .Functions}}
+## Functions
+
+{{range .}}{{template "entry" .}}{{end}}{{end}}{{with .Events}}
+## Events
+
+{{range .}}{{template "entry" .}}{{end}}{{end}}{{with .Errors}}
+## Errors
+
+{{range .}}{{template "entry" .}}{{end}}{{end}}{{with .Deployments}}
+## Deployments
+
+| Chain | Instance | Address |
+| --- | --- | --- |
+{{range .}}| {{.Chain}} | {{.Name}} | ` + "`{{.Address}}`" + ` |
+{{end}}{{end}}{{end}}
+
+{{define "index"}}# {{.Package.Name}} {{.Package.Version}}
+
+{{template "meta" .Package}}
+## Contract types
+
+{{range .Contracts}}- [{{.Name}}]({{.Name}}.md){{with .Title}}: {{.}}{{end}}
+{{end}}{{end}}
+`
+
+const htmlTemplates = `
+{{define "meta"}}{{with .Description}}<p>{{.}}</p>
+{{end}}{{with .Authors}}<p><strong>Authors:</strong> {{join . ", "}}</p>
+{{end}}{{with .License}}<p><strong>License:</strong> {{.}}</p>
+{{end}}{{with .Links}}<ul>
+{{range .}}<li><a href="{{index . 1}}">{{index . 0}}</a></li>
+{{end}}</ul>
+{{end}}{{end}}
+
+{{define "params"}}{{if .}}<table>
+<tr><th>Name</th><th>Type</th><th>Description</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td><code>{{.Type}}</code></td><td>{{.Doc}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+
+{{define "custom"}}{{range .}}<p><strong>{{index . 0}}:</strong> {{index . 1}}</p>
+{{end}}{{end}}
+
+{{define "entry"}}<h3><code>{{.Signature}}</code></h3>
+{{with .ID}}<p>{{$.IDLabel}}: <code>{{.}}</code></p>
+{{end}}{{with .StateMutability}}<p>State mutability: <code>{{.}}</code></p>
+{{end}}{{with .Notice}}<p>{{.}}</p>
+{{end}}{{with .Details}}<p>{{.}}</p>
+{{end}}{{template "custom" .Custom}}{{template "params" .Inputs}}{{if .Outputs}}<p>Returns:</p>
+{{template "params" .Outputs}}{{end}}{{with .Return}}<p>{{.}}</p>
+{{end}}{{end}}
+
+{{define "contract"}}<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Name}}</title></head>
+<body>
+<h1>{{.Name}}{{with .Title}}: {{.}}{{end}}</h1>
+<p>Package <code>{{.Package.Name}}</code> version <code>{{.Package.Version}}</code></p>
+{{with .Author}}<p><strong>Author:</strong> {{.}}</p>
+{{end}}{{with .Notice}}<p>{{.}}</p>
+{{end}}{{with .Details}}<p>{{.}}</p>
+{{end}}{{template "custom" .Custom}}{{template "meta" .Package}}{{with .Constructor}}<h2>Constructor</h2>
+{{template "entry" .}}{{end}}{{with .Functions}}<h2>Functions</h2>
+{{range .}}{{template "entry" .}}{{end}}{{end}}{{with .Events}}<h2>Events</h2>
+{{range .}}{{template "entry" .}}{{end}}{{end}}{{with .Errors}}<h2>Errors</h2>
+{{range .}}{{template "entry" .}}{{end}}{{end}}{{with .Deployments}}<h2>Deployments</h2>
+<table>
+<tr><th>Chain</th><th>Instance</th><th>Address</th></tr>
+{{range .}}<tr><td>{{.Chain}}</td><td>{{.Name}}</td><td><code>{{.Address}}</code></td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+{{end}}
+
+{{define "index"}}<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Package.Name}}</title></head>
+<body>
+<h1>{{.Package.Name}} {{.Package.Version}}</h1>
+{{template "meta" .Package}}<h2>Contract types</h2>
+<ul>
+{{range .Contracts}}<li><a href="{{.Name}}.html">{{.Name}}</a>{{with .Title}}: {{.}}{{end}}</li>
+{{end}}</ul>
+</body>
+</html>
+{{end}}
+`
+
+var (
+	markdown = texttemplate.Must(texttemplate.New("markdown").Funcs(funcs).Parse(markdownTemplates))
+	html     = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(htmlTemplates))
+)
+
+// render executes the named template of the given format
+func render(format string, name string, data interface{}) (page string, err error) {
+	var b bytes.Buffer
+	if format == HTML {
+		err = html.ExecuteTemplate(&b, name, data)
+	} else {
+		err = markdown.ExecuteTemplate(&b, name, data)
+	}
+	page = b.String()
+	return
+}
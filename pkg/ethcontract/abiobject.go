@@ -1,18 +1,235 @@
 package ethcontract
 
-// InputOutput An object with an input or output name and its primitive type
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+)
+
+// InputOutput An object with an input or output name and its type. Tuples list
+// their members in Components and event parameters set Indexed.
 type InputOutput struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Components   []*InputOutput `json:"components,omitempty"`
+	Indexed      *bool          `json:"indexed,omitempty"`
+	InternalType string         `json:"internalType,omitempty"`
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
 }
 
-// ABIObject An object that conforms to the ethereum abi schema
+// ABIObject An object that conforms to the ethereum abi schema. It represents
+// functions, constructors, fallback and receive functions, events and errors.
+// Optional members are pointers or nil slices so that an abi read from a
+// compiler is written back exactly as it was received. Constant and Payable are
+// not written by solc 0.5 and later, use IsConstant and IsPayable to read them.
 type ABIObject struct {
-	Constant        bool           `json:"constant"`
+	Anonymous       *bool          `json:"anonymous,omitempty"`
+	Constant        *bool          `json:"constant,omitempty"`
 	Inputs          []*InputOutput `json:"inputs"`
-	Name            string         `json:"name"`
+	Name            string         `json:"name,omitempty"`
 	Outputs         []*InputOutput `json:"outputs"`
-	Payable         bool           `json:"payable"`
-	StateMutability string         `json:"stateMutability"`
+	Payable         *bool          `json:"payable,omitempty"`
+	StateMutability string         `json:"stateMutability,omitempty"`
 	Type            string         `json:"type"`
 }
+
+// MarshalJSON writes the inputs and outputs only when they are not nil, so an
+// entry without them, such as a fallback function, keeps its original form
+func (a *ABIObject) MarshalJSON() ([]byte, error) {
+	type alias ABIObject
+	aux := struct {
+		*alias
+		Inputs  *[]*InputOutput `json:"inputs,omitempty"`
+		Outputs *[]*InputOutput `json:"outputs,omitempty"`
+	}{alias: (*alias)(a)}
+	if a.Inputs != nil {
+		aux.Inputs = &a.Inputs
+	}
+	if a.Outputs != nil {
+		aux.Outputs = &a.Outputs
+	}
+	return json.Marshal(aux)
+}
+
+// IsConstant returns true if the function does not modify state, either from
+// its 'constant' flag or from a 'view' or 'pure' state mutability
+func (a *ABIObject) IsConstant() bool {
+	if a.Constant != nil {
+		return *a.Constant
+	}
+	return (a.StateMutability == "view") || (a.StateMutability == "pure")
+}
+
+// IsPayable returns true if the function accepts ether, either from its
+// 'payable' flag or from a 'payable' state mutability
+func (a *ABIObject) IsPayable() bool {
+	if a.Payable != nil {
+		return *a.Payable
+	}
+	return a.StateMutability == "payable"
+}
+
+// CanonicalType returns the type used in signatures, which expands tuples into
+// the canonical types of their components, such as "(address,uint256)[]"
+func (io *InputOutput) CanonicalType() string {
+	if !strings.HasPrefix(io.Type, "tuple") {
+		return io.Type
+	}
+	types := make([]string, len(io.Components))
+	for i, v := range io.Components {
+		types[i] = v.CanonicalType()
+	}
+	return "(" + strings.Join(types, ",") + ")" + strings.TrimPrefix(io.Type, "tuple")
+}
+
+// Signature returns the canonical signature of a function, event or error, such
+// as "transfer(address,uint256)". Entries without a name return an empty string.
+func (a *ABIObject) Signature() string {
+	if a.Name == "" {
+		return ""
+	}
+	types := make([]string, len(a.Inputs))
+	for i, v := range a.Inputs {
+		types[i] = v.CanonicalType()
+	}
+	return a.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// Selector returns the 4 byte selector of a function or error as a hex string,
+// or an empty string for any other entry
+func (a *ABIObject) Selector() string {
+	if ((a.Type != "function") && (a.Type != "") && (a.Type != "error")) || (a.Name == "") {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(keccak256([]byte(a.Signature()))[:4])
+}
+
+// Topic returns the topic of an event as a hex string, or an empty string for
+// any other entry and for anonymous events, which have no topic
+func (a *ABIObject) Topic() string {
+	if (a.Type != "event") || ((a.Anonymous != nil) && *a.Anonymous) {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(keccak256([]byte(a.Signature())))
+}
+
+// ParseABI takes an abi as a json string and returns its entries
+func ParseABI(jsonstring string) (abis []*ABIObject, err error) {
+	if err = json.Unmarshal([]byte(jsonstring), &abis); err != nil {
+		err = fmt.Errorf("Error parsing abi: '%v'", err)
+	}
+	return
+}
+
+// ToGethABI converts abi entries to a go-ethereum abi.ABI. Errors and receive
+// functions have no equivalent and are left out. Tuples are not supported by the
+// go-ethereum abi package and return an error. The abi is read from GethABIJSON.
+func ToGethABI(abis []*ABIObject) (gethabi abi.ABI, err error) {
+	for _, a := range abis {
+		for _, v := range append(append([]*InputOutput{}, a.Inputs...), a.Outputs...) {
+			if strings.HasPrefix(v.Type, "tuple") {
+				err = fmt.Errorf("Tuple types are not supported by the go-ethereum abi package: '%v'", a.Signature())
+				return
+			}
+		}
+	}
+	jsonstring, err := GethABIJSON(abis)
+	if err != nil {
+		return
+	}
+	if gethabi, err = abi.JSON(strings.NewReader(jsonstring)); err != nil {
+		err = fmt.Errorf("Error creating go-ethereum abi: '%v'", err)
+	}
+	return
+}
+
+// GethABIJSON returns the abi entries as a json string read the same way by
+// go-ethereum, which only knows functions are constant from their 'constant'
+// flag. Functions without one get it from their state mutability.
+func GethABIJSON(abis []*ABIObject) (jsonstring string, err error) {
+	converted := make([]*ABIObject, len(abis))
+	for i, a := range abis {
+		c := *a
+		constant := a.IsConstant()
+		c.Constant = &constant
+		converted[i] = &c
+	}
+	b, err := json.Marshal(converted)
+	if err != nil {
+		err = fmt.Errorf("Error writing abi: '%v'", err)
+		return
+	}
+	jsonstring = string(b)
+	return
+}
+
+// FromGethABI converts a go-ethereum abi.ABI to abi entries. The constructor is
+// first, followed by the functions and then the events, each sorted by name.
+func FromGethABI(gethabi abi.ABI) (abis []*ABIObject) {
+	if len(gethabi.Constructor.Inputs) > 0 {
+		abis = append(abis, &ABIObject{
+			Inputs:          fromGethArguments(gethabi.Constructor.Inputs, false),
+			StateMutability: "nonpayable",
+			Type:            "constructor",
+		})
+	}
+	methods := make([]string, 0, len(gethabi.Methods))
+	for k := range gethabi.Methods {
+		methods = append(methods, k)
+	}
+	sort.Strings(methods)
+	for _, k := range methods {
+		m := gethabi.Methods[k]
+		constant := m.Const
+		mutability := "nonpayable"
+		if constant {
+			mutability = "view"
+		}
+		abis = append(abis, &ABIObject{
+			Constant:        &constant,
+			Inputs:          fromGethArguments(m.Inputs, false),
+			Name:            m.Name,
+			Outputs:         fromGethArguments(m.Outputs, false),
+			StateMutability: mutability,
+			Type:            "function",
+		})
+	}
+	events := make([]string, 0, len(gethabi.Events))
+	for k := range gethabi.Events {
+		events = append(events, k)
+	}
+	sort.Strings(events)
+	for _, k := range events {
+		e := gethabi.Events[k]
+		anonymous := e.Anonymous
+		abis = append(abis, &ABIObject{
+			Anonymous: &anonymous,
+			Inputs:    fromGethArguments(e.Inputs, true),
+			Name:      e.Name,
+			Type:      "event",
+		})
+	}
+	return
+}
+
+func fromGethArguments(args abi.Arguments, event bool) (ios []*InputOutput) {
+	ios = make([]*InputOutput, len(args))
+	for i, v := range args {
+		ios[i] = &InputOutput{Name: v.Name, Type: v.Type.String()}
+		if event {
+			indexed := v.Indexed
+			ios[i].Indexed = &indexed
+		}
+	}
+	return
+}
+
+func keccak256(b []byte) []byte {
+	hw := sha3.NewKeccak256()
+	hw.Write(b)
+	return hw.Sum(nil)
+}
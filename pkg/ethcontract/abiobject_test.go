@@ -0,0 +1,113 @@
+package ethcontract
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var testABI = `[{"inputs":[{"internalType":"uint256","name":"supply","type":"uint256"}],"stateMutability":"nonpayable","type":"constructor"},` +
+	`{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"from","type":"address"},` +
+	`{"indexed":true,"internalType":"address","name":"to","type":"address"},` +
+	`{"indexed":false,"internalType":"uint256","name":"value","type":"uint256"}],"name":"Transfer","type":"event"},` +
+	`{"inputs":[{"internalType":"uint256","name":"available","type":"uint256"}],"name":"InsufficientBalance","type":"error"},` +
+	`{"stateMutability":"payable","type":"fallback"},{"stateMutability":"payable","type":"receive"},` +
+	`{"inputs":[{"components":[{"internalType":"address","name":"to","type":"address"},` +
+	`{"internalType":"uint256","name":"value","type":"uint256"}],"internalType":"struct Token.Payment[]","name":"payments","type":"tuple[]"}],` +
+	`"name":"batch","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+	`{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer",` +
+	`"outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+func TestABIRoundTrip(t *testing.T) {
+	abis, err := ParseABI(testABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(abis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	json.Unmarshal(b, &got)
+	json.Unmarshal([]byte(testABI), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Got '%v', expected '%v'", string(b), testABI)
+	}
+}
+
+func TestABISignatures(t *testing.T) {
+	abis, err := ParseABI(testABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{abis[0].Signature(), ""},
+		{abis[1].Signature(), "Transfer(address,address,uint256)"},
+		{abis[1].Topic(), "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{abis[1].Selector(), ""},
+		{abis[2].Signature(), "InsufficientBalance(uint256)"},
+		{abis[5].Signature(), "batch((address,uint256)[])"},
+		{abis[6].Selector(), "0xa9059cbb"},
+		{abis[6].Topic(), ""},
+	}
+	for _, v := range tests {
+		if v.got != v.want {
+			t.Fatalf("Got '%v', expected '%v'", v.got, v.want)
+		}
+	}
+}
+
+func TestGethABI(t *testing.T) {
+	abis, err := ParseABI(testABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ToGethABI(abis); err == nil {
+		t.Fatal("Got <nil>, expected an error for a tuple input")
+	}
+	abis = append(abis[:5], abis[6:]...)
+	gethabi, err := ToGethABI(abis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gethabi.Methods["transfer"]; !ok {
+		t.Fatalf("Method 'transfer' missing from '%v'", gethabi.Methods)
+	}
+	converted := FromGethABI(gethabi)
+	if len(converted) != 3 {
+		t.Fatalf("Got '%v' entries, expected '3'", len(converted))
+	}
+	if got := converted[1].Selector(); got != "0xa9059cbb" {
+		t.Fatalf("Got '%v', expected '0xa9059cbb'", got)
+	}
+	if got := converted[2].Topic(); got != abis[1].Topic() {
+		t.Fatalf("Got '%v', expected '%v'", got, abis[1].Topic())
+	}
+	if !*converted[2].Inputs[0].Indexed || *converted[2].Inputs[2].Indexed {
+		t.Fatal("Got wrong indexed flags for 'Transfer'")
+	}
+}
+
+func TestGethABIStateMutability(t *testing.T) {
+	abis, err := ParseABI(`[{"inputs":[],"name":"total","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},` +
+		`{"inputs":[],"name":"deposit","outputs":[],"stateMutability":"payable","type":"function"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !abis[0].IsConstant() || abis[0].IsPayable() || abis[1].IsConstant() || !abis[1].IsPayable() {
+		t.Fatal("Got wrong flags from state mutability")
+	}
+	gethabi, err := ToGethABI(abis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gethabi.Methods["total"].Const || gethabi.Methods["deposit"].Const {
+		t.Fatalf("Got '%v', expected only 'total' to be constant", gethabi.Methods)
+	}
+	if abis[0].Constant != nil {
+		t.Fatalf("Got '%v', expected the abi entries to be left unchanged", *abis[0].Constant)
+	}
+}
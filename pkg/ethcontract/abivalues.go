@@ -0,0 +1,191 @@
+package ethcontract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var bigIntType = reflect.TypeOf(&big.Int{})
+
+// ParseArguments takes abi arguments and their values as strings, then returns
+// the values converted to the Go types expected by the go-ethereum abi package
+func ParseArguments(arguments abi.Arguments, values []string) (parsed []interface{}, err error) {
+	if len(values) != len(arguments) {
+		err = fmt.Errorf("Got %v arguments, expected %v", len(values), len(arguments))
+		return
+	}
+	parsed = make([]interface{}, len(values))
+	for i, v := range arguments {
+		if parsed[i], err = ParseArgument(v.Type, values[i]); err != nil {
+			err = fmt.Errorf("Argument '%v' of type '%v': '%v'", v.Name, v.Type, err)
+			return
+		}
+	}
+	return
+}
+
+// ParseArgument takes an abi type and a value as a string and returns the value
+// as the Go type expected by the go-ethereum abi package. Integers may be decimal
+// or 0x prefixed hex, bytes are hex, and arrays are json arrays such as
+// '[1,2]' or '["0x01","0x02"]'.
+func ParseArgument(t abi.Type, s string) (value interface{}, err error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return parseInteger(t, s)
+	case abi.BoolTy:
+		var b bool
+		if b, err = strconv.ParseBool(s); err != nil {
+			err = fmt.Errorf("Invalid bool '%v'", s)
+			return
+		}
+		value = b
+	case abi.StringTy:
+		value = s
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			err = fmt.Errorf("Invalid address '%v'", s)
+			return
+		}
+		value = common.HexToAddress(s)
+	case abi.HashTy:
+		value = common.HexToHash(s)
+	case abi.BytesTy:
+		value, err = parseHex(s)
+	case abi.FixedBytesTy:
+		b, retErr := parseHex(s)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		if len(b) != t.Size {
+			err = fmt.Errorf("Got %v bytes, expected %v", len(b), t.Size)
+			return
+		}
+		v := reflect.New(t.Type).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		value = v.Interface()
+	case abi.SliceTy, abi.ArrayTy:
+		return parseList(t, s)
+	default:
+		err = fmt.Errorf("Unsupported type '%v'", t)
+	}
+	return
+}
+
+// FormatValue takes a value unpacked by the go-ethereum abi package and returns
+// it as a string. Addresses, hashes and bytes are 0x prefixed hex and lists are
+// written as json style arrays.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case *big.Int:
+		return v.String()
+	case string:
+		return v
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return "0x" + hex.EncodeToString(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+	return fmt.Sprint(value)
+}
+
+func parseInteger(t abi.Type, s string) (value interface{}, err error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		err = fmt.Errorf("Invalid integer '%v'", s)
+		return
+	}
+	if (t.T == abi.UintTy) && (n.Sign() < 0) {
+		err = fmt.Errorf("Negative value '%v' for unsigned integer", s)
+		return
+	}
+	bits := n.BitLen()
+	if t.T == abi.IntTy {
+		// Signed values keep one bit for the sign, -2^(n-1) still fits
+		bits++
+		if n.Sign() < 0 {
+			bits = new(big.Int).Add(n, big.NewInt(1)).BitLen() + 1
+		}
+	}
+	if bits > t.Size {
+		err = fmt.Errorf("Value '%v' overflows %v bits", s, t.Size)
+		return
+	}
+	if t.Type == bigIntType {
+		value = n
+		return
+	}
+	v := reflect.New(t.Type).Elem()
+	if t.T == abi.UintTy {
+		v.SetUint(n.Uint64())
+	} else {
+		v.SetInt(n.Int64())
+	}
+	value = v.Interface()
+	return
+}
+
+func parseHex(s string) (b []byte, err error) {
+	if b, err = hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")); err != nil {
+		err = fmt.Errorf("Invalid hex '%v'", s)
+	}
+	return
+}
+
+func parseList(t abi.Type, s string) (value interface{}, err error) {
+	var items []json.RawMessage
+	if err = json.Unmarshal([]byte(s), &items); err != nil {
+		err = fmt.Errorf("Invalid array '%v', expected a json array", s)
+		return
+	}
+	var v reflect.Value
+	if t.T == abi.ArrayTy {
+		if len(items) != t.Size {
+			err = fmt.Errorf("Got %v items, expected %v", len(items), t.Size)
+			return
+		}
+		v = reflect.New(t.Type).Elem()
+	} else {
+		v = reflect.MakeSlice(t.Type, len(items), len(items))
+	}
+	for i, item := range items {
+		var str string
+		if json.Unmarshal(item, &str) != nil {
+			str = string(item)
+		}
+		elem, retErr := ParseArgument(*t.Elem, str)
+		if retErr != nil {
+			err = fmt.Errorf("Item %v: '%v'", i, retErr)
+			return
+		}
+		v.Index(i).Set(reflect.ValueOf(elem))
+	}
+	value = v.Interface()
+	return
+}
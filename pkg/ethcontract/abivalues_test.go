@@ -0,0 +1,77 @@
+package ethcontract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestParseArgument(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value string
+		want  string
+	}{
+		{"uint256", "1000000000000000000000", "1000000000000000000000"},
+		{"uint8", "0xff", "255"},
+		{"int8", "-128", "-128"},
+		{"bool", "true", "true"},
+		{"address", "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", "0x4F5B11c860b37b68DE6D14Fb7e7b5f18A9A1bdC0"},
+		{"bytes", "0x0102", "0x0102"},
+		{"bytes2", "0x0102", "0x0102"},
+		{"uint256[]", "[1,\"0x02\"]", "[1,2]"},
+		{"address[2]", `["0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0","0x0000000000000000000000000000000000000001"]`,
+			"[0x4F5B11c860b37b68DE6D14Fb7e7b5f18A9A1bdC0,0x0000000000000000000000000000000000000001]"},
+	}
+	for _, v := range tests {
+		typ, err := abi.NewType(v.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseArgument(typ, v.value)
+		if err != nil {
+			t.Fatalf("Got '%v' parsing '%v' as '%v'", err, v.value, v.typ)
+		}
+		if got := FormatValue(parsed); got != v.want {
+			t.Fatalf("Got '%v', expected '%v'", got, v.want)
+		}
+	}
+
+	errs := map[string]string{
+		"uint8":   "256",
+		"int8":    "128",
+		"uint256": "-1",
+		"address": "0x01",
+		"bytes2":  "0x01",
+	}
+	for k, v := range errs {
+		typ, _ := abi.NewType(k)
+		if _, err := ParseArgument(typ, v); err == nil {
+			t.Fatalf("Got <nil>, expected an error parsing '%v' as '%v'", v, k)
+		}
+	}
+}
+
+func TestParseArguments(t *testing.T) {
+	abis, err := ParseABI(testABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gethabi, err := ToGethABI(append(abis[:5], abis[6:]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := gethabi.Methods["transfer"]
+	if _, err = ParseArguments(m.Inputs, []string{"0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0"}); (err == nil) ||
+		!strings.HasPrefix(err.Error(), "Got 1 arguments, expected 2") {
+		t.Fatalf("Got '%v', expected an argument count error", err)
+	}
+	parsed, err := ParseArguments(m.Inputs, []string{"0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = gethabi.Pack("transfer", parsed...); err != nil {
+		t.Fatalf("Got '%v', expected the parsed arguments to pack", err)
+	}
+}
@@ -28,20 +28,25 @@ import (
 	"github.com/ethpm/ethpm-go/pkg/natspec"
 )
 
-// ContractType Data for a contract type included in this package
+// ContractType Data for a contract type included in this package. The compiler
+// metadata and method identifiers are not part of the v2 spec, so they are
+// written under keys prefixed with "x-".
 type ContractType struct {
 	ABI                []*ABIObject            `json:"abi,omitempty"`
 	Compiler           *bc.CompilerInformation `json:"compiler,omitempty"`
 	ContractName       string                  `json:"contract_name,omitempty"`
 	DeploymentBytecode *bc.UnlinkedBytecode    `json:"deployment_bytecode,omitempty"`
+	Metadata           string                  `json:"x-metadata,omitempty"`
+	MethodIdentifiers  map[string]string       `json:"x-method_identifiers,omitempty"`
 	Natspec            *natspec.DocUnion       `json:"natspec,omitempty"`
 	RuntimeBytecode    *bc.UnlinkedBytecode    `json:"runtime_bytecode,omitempty"`
 }
 
 // Build takes the name of the compiler used (currently only tested with solc),
 // the settings object from a standard json input, https://solidity.readthedocs.io/en/v0.4.24/using-the-compiler.html#input-description,
-// as a string, and the compiler standard json ouput as a string. It then builds
-// a contract type object
+// as a string, and a single contract object from the compiler standard json
+// output as a string. It then builds a contract type object, including the
+// metadata, method identifiers and source maps when they were requested.
 func (ct *ContractType) Build(compiler string, settingsjsonstring string, compileroutputjson string) (err error) {
 	var i map[string]interface{}
 	var b map[string]interface{}
@@ -63,6 +68,7 @@ func (ct *ContractType) Build(compiler string, settingsjsonstring string, compil
 	}
 	depbytecodebytes, _ := json.Marshal(b["bytecode"])
 	runbytecodebytes, _ := json.Marshal(b["deployedBytecode"])
+	methodidbytes, _ := json.Marshal(b["methodIdentifiers"])
 
 	if err = json.Unmarshal(abibytes, &ct.ABI); err != nil {
 		err = fmt.Errorf("Error generating ABI: '%v'", err)
@@ -78,6 +84,13 @@ func (ct *ContractType) Build(compiler string, settingsjsonstring string, compil
 		err = fmt.Errorf("Error generating UserDoc: '%v'", err)
 		return
 	}
+	if err = json.Unmarshal(methodidbytes, &ct.MethodIdentifiers); err != nil {
+		err = fmt.Errorf("Error generating method identifiers: '%v'", err)
+		return
+	}
+	if metadata, ok := i["metadata"].(string); ok {
+		ct.Metadata = metadata
+	}
 	ct.Natspec = &natspec.DocUnion{}
 	ct.Compiler = &bc.CompilerInformation{}
 	ct.DeploymentBytecode = &bc.UnlinkedBytecode{}
@@ -1,6 +1,7 @@
 package ethcontract
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
 	"testing"
@@ -76,3 +77,42 @@ func TestValidate(t *testing.T) {
 		t.Fatalf("Got '%v', expected <nil>", got)
 	}
 }
+
+func TestBuildMetadataAndSourceMaps(t *testing.T) {
+	ct := ContractType{}
+	js := `{"abi":[],"devdoc":{"methods":{}},"userdoc":{"methods":{}},"metadata":"{\"language\":\"Solidity\"}",` +
+		`"evm":{"bytecode":{"linkReferences":{},"object":"6080","sourceMap":"0:10:0:-;;"},` +
+		`"deployedBytecode":{"linkReferences":{},"object":"6001","sourceMap":"1:2:0:-"},` +
+		`"methodIdentifiers":{"plus(uint256,uint256)":"66098d4f"}}}`
+	if err := ct.Build("solc", `{}`, js); err != nil {
+		t.Fatalf("Got '%v', expected <nil>", err)
+	}
+	if ct.Metadata != `{"language":"Solidity"}` {
+		t.Fatalf("Got '%v', expected '%v'", ct.Metadata, `{"language":"Solidity"}`)
+	}
+	if got := ct.MethodIdentifiers["plus(uint256,uint256)"]; got != "66098d4f" {
+		t.Fatalf("Got '%v', expected '66098d4f'", got)
+	}
+	if ct.DeploymentBytecode.SourceMap != "0:10:0:-;;" {
+		t.Fatalf("Got '%v', expected '0:10:0:-;;'", ct.DeploymentBytecode.SourceMap)
+	}
+	if ct.RuntimeBytecode.SourceMap != "1:2:0:-" {
+		t.Fatalf("Got '%v', expected '1:2:0:-'", ct.RuntimeBytecode.SourceMap)
+	}
+	b, err := json.Marshal(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys map[string]json.RawMessage
+	if err = json.Unmarshal(b, &keys); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"x-metadata", "x-method_identifiers"} {
+		if _, ok := keys[k]; !ok {
+			t.Fatalf("Got '%v', expected the non-spec key '%v'", string(b), k)
+		}
+	}
+	if _, ok := keys["metadata"]; ok {
+		t.Fatalf("Got '%v', expected no unprefixed 'metadata' key", string(b))
+	}
+}
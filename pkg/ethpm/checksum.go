@@ -0,0 +1,240 @@
+package ethpm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/ethpm/ethpm-go/pkg/githubutils"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// Checksum algorithms for the content of sources
+const (
+	ChecksumGitBlob   = "git-blob"
+	ChecksumIPFS      = "ipfs"
+	ChecksumKeccak256 = "keccak256"
+)
+
+// ChecksumFile is the sidecar of ethpm.json recording the checksums of sources,
+// which version 2 manifests have no field for
+const ChecksumFile = "ethpm.checksums.json"
+
+// httpClient fetches sources and manifests, giving up on unresponsive hosts
+var httpClient = &http.Client{Timeout: time.Minute}
+
+// Checksum is the hash of the content of a source, as in the checksum object of
+// version 3 manifests. Keccak256 hashes are 0x prefixed hex, IPFS hashes are
+// CIDv0 such as "Qm..." and git blob hashes are the sha1 git gives the content.
+type Checksum struct {
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash"`
+}
+
+// NewChecksum takes a checksum algorithm and the content of a source and
+// returns its Checksum
+func NewChecksum(algorithm string, content []byte) (c *Checksum, err error) {
+	c = &Checksum{Algorithm: algorithm}
+	switch algorithm {
+	case ChecksumKeccak256:
+		hw := sha3.NewKeccak256()
+		hw.Write(content)
+		c.Hash = "0x" + hex.EncodeToString(hw.Sum(nil))
+	case ChecksumGitBlob:
+		c.Hash = githubutils.GitBlobHash(content)
+	case ChecksumIPFS:
+		c.Hash, err = solcutils.IPFSHash(content)
+	default:
+		err = fmt.Errorf("Unsupported checksum algorithm '%v'", algorithm)
+	}
+	return
+}
+
+// Verify makes sure the content has this checksum
+func (c *Checksum) Verify(content []byte) (err error) {
+	got, err := NewChecksum(c.Algorithm, content)
+	if err != nil {
+		return
+	}
+	if !strings.EqualFold(got.Hash, c.Hash) {
+		err = fmt.Errorf("%v checksum is '%v', expected '%v'", c.Algorithm, got.Hash, c.Hash)
+	}
+	return
+}
+
+// AddSourceChecksum takes the key of a source, its content and a checksum
+// algorithm, then records the checksum of the source
+func (p *PackageManifest) AddSourceChecksum(key string, content []byte, algorithm string) (err error) {
+	c, err := NewChecksum(algorithm, content)
+	if err != nil {
+		return
+	}
+	if len(p.SourceChecksums) == 0 {
+		p.SourceChecksums = make(map[string]*Checksum)
+	}
+	p.SourceChecksums[key] = c
+	return
+}
+
+// ReadChecksums reads the checksums of sources from the sidecar file in the
+// given directory, which can be empty for the current working directory
+func (p *PackageManifest) ReadChecksums(directoryname string) (err error) {
+	b, err := ioutil.ReadFile(filepath.Join(directoryname, ChecksumFile))
+	if err != nil {
+		err = fmt.Errorf("Could not read %v: '%v'", ChecksumFile, err)
+		return
+	}
+	if err = json.Unmarshal(b, &p.SourceChecksums); err != nil {
+		err = fmt.Errorf("Could not parse %v: '%v'", ChecksumFile, err)
+	}
+	return
+}
+
+// WriteChecksums writes the checksums of sources to the sidecar file in the
+// given directory, which can be empty for the current working directory
+func (p *PackageManifest) WriteChecksums(directoryname string) (err error) {
+	b, err := json.Marshal(p.SourceChecksums)
+	if err != nil {
+		return
+	}
+	if err = ioutil.WriteFile(filepath.Join(directoryname, ChecksumFile), b, 0644); err != nil {
+		err = fmt.Errorf("Could not write %v: '%v'", ChecksumFile, err)
+	}
+	return
+}
+
+// VerifySources fetches the content of every source and checks it against its
+// recorded checksum. Inlined sources are used as is, relative paths and file
+// uris are read from the filesystem relative to manifestdir, http and https uris
+// are downloaded, git uris, see githubutils.GitURI, are read from their
+// repository, and ipfs uris are fetched through the IPFS gateway, which can
+// be nil for the default. IPFS and GitHub blob uris are checked against the hash
+// in the uri as well. Sources without a checksum, other than ipfs and GitHub
+// blob uris, are an error.
+func (p *PackageManifest) VerifySources(manifestdir string, gateway *solcutils.MetadataGateway) (err error) {
+	if gateway == nil {
+		gateway = solcutils.NewMetadataGateway("", "")
+	}
+	keys := make([]string, 0, len(p.Sources))
+	for k := range p.Sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	repos := githubutils.Repositories{}
+	for _, k := range keys {
+		v := p.Sources[k]
+		checksums := uriChecksums(v)
+		if c, ok := p.SourceChecksums[k]; ok {
+			checksums = append([]*Checksum{c}, checksums...)
+		}
+		if len(checksums) == 0 {
+			err = fmt.Errorf("No checksum recorded for source '%v'", k)
+			return
+		}
+		content, retErr := fetchSource(v, manifestdir, gateway, repos)
+		if retErr != nil {
+			err = fmt.Errorf("Could not fetch source '%v': '%v'", k, retErr)
+			return
+		}
+		for _, c := range checksums {
+			if retErr := c.Verify(content); retErr != nil {
+				err = fmt.Errorf("Source '%v' does not match: '%v'", k, retErr)
+				return
+			}
+		}
+	}
+	return
+}
+
+// gitHosts are the hosts whose http and https blob, tree and commit uris are
+// read from the git repository rather than downloaded
+var gitHosts = map[string]bool{
+	"github.com": true,
+	"gitlab.com": true,
+}
+
+// uriChecksums returns the checksums carried by a uri, the hash of ipfs and
+// GitHub blob uris
+func uriChecksums(value string) (checksums []*Checksum) {
+	if strings.HasPrefix(value, "ipfs://") {
+		checksums = append(checksums, &Checksum{Algorithm: ChecksumIPFS, Hash: strings.Trim(strings.TrimPrefix(value, "ipfs://"), "/")})
+	}
+	if _, _, blobhash, err := githubutils.ParseGithubBlobURI(value); err == nil {
+		checksums = append(checksums, &Checksum{Algorithm: ChecksumGitBlob, Hash: blobhash})
+	}
+	return
+}
+
+// fetchSource returns the content of a source value, which is either a uri, a
+// path relative to the manifest directory or the inlined source itself. Paths
+// which do not exist are an error. Blob, tree and commit uris of git
+// repositories are read from the repository if they are file, git or ssh uris
+// or are hosted on one of gitHosts, opening each repository once through repos,
+// and GitHub api blob uris are verified against their hash.
+func fetchSource(value string, manifestdir string, gateway *solcutils.MetadataGateway, repos githubutils.Repositories) (content []byte, err error) {
+	uri, retErr := url.Parse(value)
+	if (retErr != nil) || !uri.IsAbs() {
+		if !isSourceLocation(value) {
+			content = []byte(value)
+			return
+		}
+		path := filepath.Join(manifestdir, filepath.FromSlash(value))
+		if content, err = ioutil.ReadFile(path); os.IsNotExist(err) {
+			err = fmt.Errorf("Source '%v' does not exist", path)
+		}
+		return
+	}
+	switch uri.Scheme {
+	case "file":
+		path := uri.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(manifestdir, path)
+		}
+		if _, retErr = os.Stat(filepath.FromSlash(path)); os.IsNotExist(retErr) && githubutils.IsGitURI(value) {
+			return repos.ReadGitURI(value)
+		}
+		return ioutil.ReadFile(filepath.FromSlash(path))
+	case "ipfs":
+		return httpGet(gateway.IPFS + "/ipfs/" + strings.Trim(strings.TrimPrefix(value, "ipfs://"), "/"))
+	case "git", "ssh":
+		return repos.ReadGitURI(value)
+	case "http", "https":
+		if githubutils.IsGithubBlobURI(value) {
+			return githubutils.ReadGithubBlobURI(value)
+		}
+		if gitHosts[uri.Hostname()] && githubutils.IsGitURI(value) {
+			return repos.ReadGitURI(value)
+		}
+		return httpGet(value)
+	}
+	err = fmt.Errorf("Unsupported uri scheme '%v'", uri.Scheme)
+	return
+}
+
+// isSourceLocation returns true if a source value is a path or a uri rather
+// than the inlined source, which spans lines or contains spaces
+func isSourceLocation(value string) bool {
+	return (value != "") && !strings.ContainsAny(value, " \t\r\n")
+}
+
+func httpGet(uri string) (content []byte, err error) {
+	resp, err := httpClient.Get(uri)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Got status '%v' for '%v'", resp.Status, uri)
+		return
+	}
+	return ioutil.ReadAll(resp.Body)
+}
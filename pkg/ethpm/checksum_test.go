@@ -0,0 +1,132 @@
+package ethpm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+func TestNewChecksum(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		content   string
+		want      string
+	}{
+		{ChecksumKeccak256, "", "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{ChecksumIPFS, "", "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH"},
+		{ChecksumIPFS, "hello world\n", "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"},
+		{ChecksumGitBlob, "hello world\n", "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"},
+	}
+	for _, tt := range tests {
+		c, err := NewChecksum(tt.algorithm, []byte(tt.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Hash != tt.want {
+			t.Fatalf("Got '%v', expected '%v'", c.Hash, tt.want)
+		}
+		if err = c.Verify([]byte(tt.content + " ")); err == nil {
+			t.Fatalf("Got '%v', expected a mismatch for changed content", err)
+		}
+	}
+	if _, err := NewChecksum("md5", nil); err == nil {
+		t.Fatalf("Got '%v', expected an error for an unsupported algorithm", err)
+	}
+}
+
+func TestVerifySources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-sources")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	owned := "contract Owned {}\n"
+	served := "library SafeMath {}\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "Owned.sol"), []byte(owned), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ipfs/QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o" {
+			w.Write([]byte("hello world\n"))
+			return
+		}
+		w.Write([]byte(served))
+	}))
+	defer srv.Close()
+
+	p := &PackageManifest{Sources: map[string]string{
+		"./Owned.sol":    "./Owned.sol",
+		"./SafeMath.sol": srv.URL + "/SafeMath.sol",
+		"./Math.sol":     srv.URL + "/ethpm/math/blob/master/Math.sol",
+		"./Token.sol":    "contract Token {}",
+		"./Hello.txt":    "ipfs://QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+	}}
+	p.AddSourceChecksum("./Owned.sol", []byte(owned), ChecksumKeccak256)
+	p.AddSourceChecksum("./SafeMath.sol", []byte(served), ChecksumIPFS)
+	// Blob uris of hosts other than the known git hosts are downloaded
+	p.AddSourceChecksum("./Math.sol", []byte(served), ChecksumKeccak256)
+	p.AddSourceChecksum("./Token.sol", []byte("contract Token {}"), ChecksumKeccak256)
+	gateway := solcutils.NewMetadataGateway(srv.URL, "")
+	if err = p.VerifySources(dir, gateway); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = p.WriteChecksums(dir); err != nil {
+		t.Fatal(err)
+	}
+	read := &PackageManifest{Sources: p.Sources}
+	if err = read.ReadChecksums(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := read.SourceChecksums["./Owned.sol"].Hash, p.SourceChecksums["./Owned.sol"].Hash; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	served = "library SafeMath { function tampered() {} }\n"
+	if err = p.VerifySources(dir, gateway); err == nil {
+		t.Fatalf("Got '%v', expected an error for a tampered source", err)
+	}
+	served = "library SafeMath {}\n"
+	p.Sources["./Missing.sol"] = "./Missing.sol"
+	p.AddSourceChecksum("./Missing.sol", []byte("./Missing.sol"), ChecksumKeccak256)
+	if err = p.VerifySources(dir, gateway); (err == nil) || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("Got '%v', expected an error for a missing source", err)
+	}
+	delete(p.Sources, "./Missing.sol")
+	delete(p.SourceChecksums, "./Missing.sol")
+	delete(p.SourceChecksums, "./Owned.sol")
+	if err = p.VerifySources(dir, gateway); err == nil {
+		t.Fatalf("Got '%v', expected an error for a source without a checksum", err)
+	}
+}
+
+func TestWriteToDiskChecksums(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := &PackageManifest{
+		ManifestVersion: "2",
+		PackageName:     "owned",
+		Version:         "1.0.0",
+		Sources:         map[string]string{"./Owned.sol": "contract Owned {}\n"},
+	}
+	if err = p.AddSourceChecksum("./Owned.sol", []byte(p.Sources["./Owned.sol"]), ChecksumKeccak256); err != nil {
+		t.Fatal(err)
+	}
+	if err = p.WriteToDisk(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ethpm.json", ChecksumFile} {
+		if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("Got '%v', expected '%v' in '%v'", err, name, dir)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ethpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethpm/ethpm-go/pkg/compilerutils"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// CompileProject takes the name of the installed compiler, such as solc or
+// vyper, the project directory, which can be empty and the current working
+// directory will be used, and the compiler settings, which can be nil for the
+// defaults. It compiles every source in this manifest written in the language of
+// the compiler, together with every file Solidity sources import from the
+// project or from the build dependencies installed in the 'ethpm-dependencies'
+// directory, and returns the standard input and standard output json objects as
+// strings.
+func (p *PackageManifest) CompileProject(compiler string, projectdir string, settings *solcutils.SolcSettings) (stdinjson string, stdoutjson string, err error) {
+	if projectdir == "" {
+		if projectdir, err = os.Getwd(); err != nil {
+			err = fmt.Errorf("Could not get working directory: '%v'", err)
+			return
+		}
+	}
+	sources, err := p.projectSources(projectdir)
+	if err != nil {
+		return
+	}
+	c, err := compilerutils.NewCompiler(compiler, projectdir)
+	if err != nil {
+		return
+	}
+	stdinjson, stdoutjson, err = compileSources(c, projectdir, sources, p.dependencyNames(), settings)
+	if err != nil {
+		err = fmt.Errorf("Error compiling project: '%v'", err)
+	}
+	return
+}
+
+// compileSources compiles the sources written in the language of the compiler,
+// along with the files Solidity sources import from dir, and returns the
+// standard input and standard output json objects as strings
+func compileSources(c compilerutils.Compiler, dir string, sources map[string]string, dependencies []string, settings *solcutils.SolcSettings) (stdinjson string, stdoutjson string, err error) {
+	if c.Language() == compilerutils.Vyper {
+		input := make(map[string]map[string]string)
+		for k, v := range sources {
+			if compilerutils.SourceLanguage(k) == compilerutils.Vyper {
+				input[k] = map[string]string{"content": v}
+			}
+		}
+		if stdinjson, err = compilerutils.BuildInput(c, input, settings); err != nil {
+			return
+		}
+	} else {
+		input := make(map[string]string)
+		for k, v := range sources {
+			if compilerutils.SourceLanguage(k) == compilerutils.Solidity {
+				input[k] = v
+			}
+		}
+		si, retErr := solcutils.BuildProjectInput(dir, input, dependencies, settings)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		b, _ := json.Marshal(si)
+		stdinjson = string(b)
+	}
+	stdoutjson, err = c.CompileStandardJSON(stdinjson)
+	return
+}
+
+// projectSources returns the content of every source in this manifest and every
+// inlined source of its installed build dependencies, keyed by source unit name
+func (p *PackageManifest) projectSources(projectdir string) (sources map[string]string, err error) {
+	sources = make(map[string]string)
+	for k, v := range p.Sources {
+		if sources[solcutils.SourceUnitName(k)], err = sourceContent(projectdir, k, v); err != nil {
+			return
+		}
+	}
+	for _, name := range p.dependencyNames() {
+		dep, retErr := readInstalledManifest(projectdir, name)
+		if os.IsNotExist(retErr) {
+			continue
+		} else if retErr != nil {
+			err = fmt.Errorf("Could not read installed dependency '%v': '%v'", name, retErr)
+			return
+		}
+		depdir := filepath.Join(projectdir, solcutils.DependencyDir, name)
+		for k, v := range dep.Sources {
+			key := solcutils.DependencyDir + "/" + name + "/" + solcutils.SourceUnitName(k)
+			if sources[key], err = sourceContent(depdir, k, v); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// dependencyNames returns the sorted names of this manifest's build dependencies
+func (p *PackageManifest) dependencyNames() (names []string) {
+	for k := range p.BuildDependencies {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return
+}
+
+// sourceContent returns the content of a source value, which is either the
+// inlined source itself or a path relative to dir. Paths which do not exist
+// are an error.
+func sourceContent(dir string, key string, value string) (content string, err error) {
+	if uri, retErr := url.Parse(value); (retErr == nil) && uri.IsAbs() {
+		err = fmt.Errorf("Source '%v' is located at uri '%v' and needs to be extracted before compiling", key, value)
+		return
+	}
+	if !isSourceLocation(value) {
+		content = value
+		return
+	}
+	location := filepath.Join(dir, filepath.FromSlash(value))
+	b, err := ioutil.ReadFile(location)
+	if os.IsNotExist(err) {
+		err = fmt.Errorf("Source '%v' at '%v' does not exist", key, location)
+		return
+	} else if err != nil {
+		err = fmt.Errorf("Could not read source '%v': '%v'", key, err)
+		return
+	}
+	content = string(b)
+	return
+}
+
+// readInstalledManifest reads the manifest of the build dependency installed
+// under the given name in the project's 'ethpm-dependencies' directory
+func readInstalledManifest(projectdir string, name string) (pm *PackageManifest, err error) {
+	b, err := ioutil.ReadFile(filepath.Join(projectdir, solcutils.DependencyDir, name, "ethpm.json"))
+	if err != nil {
+		return
+	}
+	pm = &PackageManifest{}
+	err = pm.Read(string(b))
+	return
+}
@@ -0,0 +1,58 @@
+package ethpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = os.MkdirAll(filepath.Join(dir, "contracts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	token := "contract Token {}\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "contracts", "Token.sol"), []byte(token), 0644); err != nil {
+		t.Fatal(err)
+	}
+	depdir := filepath.Join(dir, "ethpm-dependencies", "basic-math")
+	if err = os.MkdirAll(depdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dep := `{"manifest_version":"2","package_name":"basic-math","version":"1.0.0",` +
+		`"sources":{"./contracts/BasicMathLib.sol":"library BasicMathLib {}\n"}}`
+	if err = ioutil.WriteFile(filepath.Join(depdir, "ethpm.json"), []byte(dep), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := PackageManifest{}
+	p.Sources = map[string]string{
+		"./contracts/Token.sol":  "./contracts/Token.sol",
+		"./contracts/Inline.sol": "contract Inline {}\n",
+	}
+	p.AddDependency("basic-math", "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW")
+
+	got, err := p.projectSources(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["contracts/Token.sol"] != token {
+		t.Fatalf("Got '%v', expected '%v'", got["contracts/Token.sol"], token)
+	}
+	if got["contracts/Inline.sol"] != "contract Inline {}\n" {
+		t.Fatalf("Got '%v', expected the inlined source", got["contracts/Inline.sol"])
+	}
+	if got["ethpm-dependencies/basic-math/contracts/BasicMathLib.sol"] != "library BasicMathLib {}\n" {
+		t.Fatalf("Got '%v', expected the dependency source", got)
+	}
+
+	p.Sources["./contracts/Remote.sol"] = "https://github.com/ethpm/ethpm-go"
+	if _, err = p.projectSources(dir); err == nil {
+		t.Fatal("Got <nil>, expected an error for a uri source")
+	}
+}
@@ -0,0 +1,287 @@
+package ethpm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// DecodedArgument is a single named value decoded from call data, return data,
+// revert data or a log
+type DecodedArgument struct {
+	Indexed bool
+	Name    string
+	Type    string
+	Value   interface{}
+}
+
+// Decoded is the function, error or event some data was decoded as, along with
+// the contract type defining it and the decoded arguments
+type Decoded struct {
+	Arguments    []*DecodedArgument
+	ContractType string
+	Kind         string
+	Signature    string
+}
+
+// String returns the signature followed by one line for each argument
+func (d *Decoded) String() string {
+	var b strings.Builder
+	if d.ContractType != "" {
+		b.WriteString(d.ContractType + ".")
+	}
+	b.WriteString(d.Signature)
+	for _, v := range d.Arguments {
+		indexed := ""
+		if v.Indexed {
+			indexed = " indexed"
+		}
+		fmt.Fprintf(&b, "\n  %v (%v%v): %v", v.Name, v.Type, indexed, ethcontract.FormatValue(v.Value))
+	}
+	return b.String()
+}
+
+// decoderEntry is an abi entry indexed by its selector or topic
+type decoderEntry struct {
+	contracttype string
+	entry        *ethcontract.ABIObject
+}
+
+// Decoder decodes raw data using the abi of every contract type of a package
+// and its installed build dependencies, indexed by selector and topic
+type Decoder struct {
+	errors    map[string][]*decoderEntry
+	events    map[string][]*decoderEntry
+	functions map[string][]*decoderEntry
+}
+
+// builtinErrors are the errors the compiler reverts with on its own
+var builtinErrors = []*ethcontract.ABIObject{
+	{Name: "Error", Type: "error", Inputs: []*ethcontract.InputOutput{{Name: "message", Type: "string"}}},
+	{Name: "Panic", Type: "error", Inputs: []*ethcontract.InputOutput{{Name: "code", Type: "uint256"}}},
+}
+
+// NewDecoder takes the project directory containing the installed build
+// dependencies, which can be empty for the working directory, and returns a
+// decoder for every abi entry of this package and its dependencies. Dependencies
+// which are not installed are skipped, those which cannot be read return an error.
+func (p *PackageManifest) NewDecoder(projectdir string) (d *Decoder, err error) {
+	d = &Decoder{
+		errors:    make(map[string][]*decoderEntry),
+		events:    make(map[string][]*decoderEntry),
+		functions: make(map[string][]*decoderEntry),
+	}
+	for _, v := range builtinErrors {
+		d.add("", v)
+	}
+	err = d.addPackage(p, projectdir, "")
+	return
+}
+
+// addPackage indexes the contract types of a package and, recursively, those of
+// its installed build dependencies under the given reference prefix
+func (d *Decoder) addPackage(p *PackageManifest, projectdir string, prefix string) (err error) {
+	names := make([]string, 0, len(p.ContractTypes))
+	for k := range p.ContractTypes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range p.ContractTypes[name].ABI {
+			d.add(prefix+name, v)
+		}
+	}
+	for _, name := range p.dependencyNames() {
+		dep, retErr := readInstalledManifest(projectdir, name)
+		if os.IsNotExist(retErr) {
+			continue
+		} else if retErr != nil {
+			err = fmt.Errorf("Could not read installed dependency '%v': '%v'", name, retErr)
+			return
+		}
+		depdir := filepath.Join(projectdir, solcutils.DependencyDir, name)
+		if err = d.addPackage(dep, depdir, prefix+name+":"); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (d *Decoder) add(contracttype string, entry *ethcontract.ABIObject) {
+	de := &decoderEntry{contracttype: contracttype, entry: entry}
+	switch entry.Type {
+	case "function", "":
+		d.functions[entry.Selector()] = append(d.functions[entry.Selector()], de)
+	case "error":
+		d.errors[entry.Selector()] = append(d.errors[entry.Selector()], de)
+	case "event":
+		if topic := entry.Topic(); topic != "" {
+			d.events[topic] = append(d.events[topic], de)
+		}
+	}
+	return
+}
+
+// DecodeCalldata decodes the input data of a transaction or call
+func (d *Decoder) DecodeCalldata(calldata []byte) (decoded *Decoded, err error) {
+	if len(calldata) < 4 {
+		err = fmt.Errorf("Call data is %v bytes, expected at least 4", len(calldata))
+		return
+	}
+	return decodeEntries(d.functions, calldata[:4], "function", func(e *ethcontract.ABIObject) []*ethcontract.InputOutput {
+		return e.Inputs
+	}, calldata[4:])
+}
+
+// DecodeReturn decodes the return data of a call using the function selected by
+// the call data
+func (d *Decoder) DecodeReturn(calldata []byte, returndata []byte) (decoded *Decoded, err error) {
+	if len(calldata) < 4 {
+		err = fmt.Errorf("Call data is %v bytes, expected at least 4", len(calldata))
+		return
+	}
+	return decodeEntries(d.functions, calldata[:4], "function", func(e *ethcontract.ABIObject) []*ethcontract.InputOutput {
+		return e.Outputs
+	}, returndata)
+}
+
+// DecodeRevert decodes the data of a reverted call, which is either an
+// Error(string), a Panic(uint256) or a custom error from one of the abis
+func (d *Decoder) DecodeRevert(revertdata []byte) (decoded *Decoded, err error) {
+	if len(revertdata) < 4 {
+		err = fmt.Errorf("Revert data is %v bytes, expected at least 4", len(revertdata))
+		return
+	}
+	return decodeEntries(d.errors, revertdata[:4], "error", func(e *ethcontract.ABIObject) []*ethcontract.InputOutput {
+		return e.Inputs
+	}, revertdata[4:])
+}
+
+// DecodeLog decodes an event log from its topics and data. Indexed arguments of
+// dynamic types only have their hash in the topics and are decoded as that hash.
+func (d *Decoder) DecodeLog(topics []common.Hash, data []byte) (decoded *Decoded, err error) {
+	if len(topics) == 0 {
+		err = fmt.Errorf("Log has no topics, anonymous events cannot be decoded")
+		return
+	}
+	entries := d.events[topics[0].Hex()]
+	if len(entries) == 0 {
+		err = fmt.Errorf("No event with topic '%v'", topics[0].Hex())
+		return
+	}
+	for _, de := range entries {
+		if decoded, err = decodeLogEntry(de, topics[1:], data); err == nil {
+			return
+		}
+	}
+	return
+}
+
+// decodeEntries tries every entry with the given selector until one decodes
+func decodeEntries(index map[string][]*decoderEntry,
+	selector []byte,
+	kind string,
+	arguments func(e *ethcontract.ABIObject) []*ethcontract.InputOutput,
+	data []byte,
+) (decoded *Decoded, err error) {
+	entries := index["0x"+hex.EncodeToString(selector)]
+	if len(entries) == 0 {
+		err = fmt.Errorf("No %v with selector '0x%v'", kind, hex.EncodeToString(selector))
+		return
+	}
+	for _, de := range entries {
+		ios := arguments(de.entry)
+		values, retErr := decodeValues(ios, data)
+		if retErr != nil {
+			err = fmt.Errorf("Could not decode '%v': '%v'", de.entry.Signature(), retErr)
+			continue
+		}
+		decoded = &Decoded{ContractType: de.contracttype, Kind: kind, Signature: de.entry.Signature()}
+		for i, v := range ios {
+			decoded.Arguments = append(decoded.Arguments, &DecodedArgument{Name: v.Name, Type: v.Type, Value: values[i]})
+		}
+		err = nil
+		return
+	}
+	return
+}
+
+func decodeLogEntry(de *decoderEntry, topics []common.Hash, data []byte) (decoded *Decoded, err error) {
+	var indexed, nonindexed []*ethcontract.InputOutput
+	for _, v := range de.entry.Inputs {
+		if (v.Indexed != nil) && *v.Indexed {
+			indexed = append(indexed, v)
+		} else {
+			nonindexed = append(nonindexed, v)
+		}
+	}
+	if len(indexed) != len(topics) {
+		err = fmt.Errorf("Got %v indexed topics, expected %v for '%v'", len(topics), len(indexed), de.entry.Signature())
+		return
+	}
+	values := make(map[*ethcontract.InputOutput]interface{})
+	for i, v := range indexed {
+		if isDynamic(v.Type) {
+			values[v] = topics[i]
+			continue
+		}
+		topicvalues, retErr := decodeValues([]*ethcontract.InputOutput{v}, topics[i].Bytes())
+		if retErr != nil {
+			err = fmt.Errorf("Could not decode topic '%v' of '%v': '%v'", v.Name, de.entry.Signature(), retErr)
+			return
+		}
+		values[v] = topicvalues[0]
+	}
+	datavalues, err := decodeValues(nonindexed, data)
+	if err != nil {
+		err = fmt.Errorf("Could not decode data of '%v': '%v'", de.entry.Signature(), err)
+		return
+	}
+	for i, v := range nonindexed {
+		values[v] = datavalues[i]
+	}
+	decoded = &Decoded{ContractType: de.contracttype, Kind: "event", Signature: de.entry.Signature()}
+	for _, v := range de.entry.Inputs {
+		decoded.Arguments = append(decoded.Arguments, &DecodedArgument{
+			Indexed: (v.Indexed != nil) && *v.Indexed,
+			Name:    v.Name,
+			Type:    v.Type,
+			Value:   values[v],
+		})
+	}
+	return
+}
+
+// decodeValues decodes abi encoded data according to the given inputs or outputs
+func decodeValues(ios []*ethcontract.InputOutput, data []byte) (values []interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Malformed data: '%v'", r)
+		}
+	}()
+	plain := make([]*ethcontract.InputOutput, len(ios))
+	for i, v := range ios {
+		plain[i] = &ethcontract.InputOutput{Name: v.Name, Type: v.Type}
+	}
+	b, _ := json.Marshal(plain)
+	var args abi.Arguments
+	if err = json.Unmarshal(b, &args); err != nil {
+		return
+	}
+	values, err = args.UnpackValues(data)
+	return
+}
+
+// isDynamic reports whether an indexed value of the type is stored as a hash
+func isDynamic(t string) bool {
+	return (t == "string") || (t == "bytes") || strings.HasSuffix(t, "]") || strings.HasPrefix(t, "tuple")
+}
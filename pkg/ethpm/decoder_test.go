@@ -0,0 +1,136 @@
+package ethpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+)
+
+const tokenABI = `[{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],` +
+	`"name":"transfer","outputs":[{"name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},` +
+	`{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},` +
+	`{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"},` +
+	`{"inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}],"name":"InsufficientBalance","type":"error"}]`
+
+func testDecoder(t *testing.T) *Decoder {
+	abis, err := ethcontract.ParseABI(tokenABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &PackageManifest{PackageName: "token"}
+	p.ContractTypes = map[string]*ethcontract.ContractType{"Token": {ABI: abis}}
+	d, err := p.NewDecoder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	d := testDecoder(t)
+	calldata := common.FromHex("0xa9059cbb0000000000000000000000004f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0" +
+		"000000000000000000000000000000000000000000000000000000000000000a")
+	got, err := d.DecodeCalldata(calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Token.transfer(address,uint256)\n  to (address): 0x4F5B11c860b37b68DE6D14Fb7e7b5f18A9A1bdC0\n  value (uint256): 10"
+	if got.String() != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	ret, err := d.DecodeReturn(calldata, common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Arguments[0].Name != "success" || ret.Arguments[0].Value != true {
+		t.Fatalf("Got '%v', expected 'success: true'", ret)
+	}
+
+	if _, err = d.DecodeCalldata(common.FromHex("0x12345678")); err == nil {
+		t.Fatal("Got <nil>, expected an error for an unknown selector")
+	}
+}
+
+func TestDecodeRevert(t *testing.T) {
+	d := testDecoder(t)
+	reason := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000d" +
+		"4e6f7420746865206f776e657200000000000000000000000000000000000000"
+	got, err := d.DecodeRevert(common.FromHex(reason))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "Error(string)\n  message (string): Not the owner" {
+		t.Fatalf("Got '%v', expected 'Error(string)'", got)
+	}
+
+	panicdata := "0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011"
+	if got, err = d.DecodeRevert(common.FromHex(panicdata)); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "Panic(uint256)\n  code (uint256): 17" {
+		t.Fatalf("Got '%v', expected 'Panic(uint256)'", got)
+	}
+
+	custom := "0xcf479181" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002"
+	if got, err = d.DecodeRevert(common.FromHex(custom)); err != nil {
+		t.Fatal(err)
+	}
+	if got.Signature != "InsufficientBalance(uint256,uint256)" || got.ContractType != "Token" {
+		t.Fatalf("Got '%v', expected 'Token.InsufficientBalance(uint256,uint256)'", got)
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	d := testDecoder(t)
+	topics := []common.Hash{
+		common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+		common.HexToHash("0x0000000000000000000000004f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0"),
+		common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+	}
+	got, err := d.DecodeLog(topics, common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000064"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Token.Transfer(address,address,uint256)\n" +
+		"  from (address indexed): 0x4F5B11c860b37b68DE6D14Fb7e7b5f18A9A1bdC0\n" +
+		"  to (address indexed): 0x0000000000000000000000000000000000000001\n" +
+		"  value (uint256): 100"
+	if got.String() != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if _, err = d.DecodeLog(topics[:2], nil); err == nil {
+		t.Fatal("Got <nil>, expected an error for a missing topic")
+	}
+}
+
+func TestNewDecoderDependencies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-decoder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := &PackageManifest{PackageName: "token"}
+	p.AddDependency("owned", "ipfs://QmUwVUMVtkVctrLDeL12SoeCPUacELBU8nAxRtHUzvtjND")
+	if _, err = p.NewDecoder(dir); err != nil {
+		t.Fatalf("Got '%v', expected a missing dependency to be skipped", err)
+	}
+	depdir := filepath.Join(dir, "ethpm-dependencies", "owned")
+	if err = os.MkdirAll(depdir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(depdir, "ethpm.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.NewDecoder(dir); err == nil {
+		t.Fatal("Got <nil>, expected an error for an unreadable dependency")
+	}
+}
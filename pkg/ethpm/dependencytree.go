@@ -0,0 +1,140 @@
+package ethpm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/ethregexlib"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// DependencyTree resolves references into the build dependencies of a package,
+// such as the link value "a:b:Contract", which names the contract instance
+// "Contract" of the build dependency "b" of the build dependency "a". Manifests
+// of dependencies are looked up in memory first, keyed by their path in the
+// tree such as "a:b", then in the install roots in order, which contain the
+// dependencies installed in 'ethpm-dependencies'. An empty install root is the
+// working directory.
+type DependencyTree struct {
+	InstallRoots []string
+	Manifests    map[string]*PackageManifest
+	root         *PackageManifest
+}
+
+// NewDependencyTree takes the root package of the tree and the directories
+// its dependencies are installed in, and returns a DependencyTree. Without
+// install roots the working directory is used.
+func NewDependencyTree(p *PackageManifest, installroots ...string) *DependencyTree {
+	if len(installroots) == 0 {
+		installroots = []string{"."}
+	}
+	return &DependencyTree{
+		InstallRoots: installroots,
+		Manifests:    make(map[string]*PackageManifest),
+		root:         p,
+	}
+}
+
+// AddManifest adds the manifest of the dependency at the given path of the tree,
+// such as "a" or "a:b", so it does not have to be installed
+func (t *DependencyTree) AddManifest(path string, pm *PackageManifest) {
+	t.Manifests[path] = pm
+}
+
+// Manifest takes the path of a dependency in the tree, such as "a:b", and returns
+// its manifest. Every package along the path must list the next one as a build
+// dependency. An empty path returns the root package.
+func (t *DependencyTree) Manifest(path string) (pm *PackageManifest, err error) {
+	pm = t.root
+	if path == "" {
+		return
+	}
+	names := strings.Split(path, ":")
+	for i, name := range names {
+		current := strings.Join(names[:i+1], ":")
+		if _, ok := pm.BuildDependencies[name]; !ok {
+			err = fmt.Errorf("No build dependency '%v' in package '%v' while resolving '%v'", name, pm.PackageName, current)
+			return
+		}
+		if dep, ok := t.Manifests[current]; ok {
+			pm = dep
+			continue
+		}
+		if pm, err = t.readInstalled(names[:i+1]); err != nil {
+			return
+		}
+		t.Manifests[current] = pm
+	}
+	return
+}
+
+// ResolveInstance takes a blockchain uri and a link value reference, such as
+// "Contract" or "a:b:Contract", and returns the contract instance it names along
+// with the blockchain uri of the deployments it was found in. Deployments of a
+// dependency recorded at a different block of the same chain are used when the
+// exact blockchain uri is missing.
+func (t *DependencyTree) ResolveInstance(blockchainuri string, reference string) (ci *ethcontract.ContractInstance, chain string, err error) {
+	if err = ethregexlib.CheckDependencyTree(reference); err != nil {
+		return
+	}
+	path, name := "", reference
+	if i := strings.LastIndex(reference, ":"); i >= 0 {
+		path, name = reference[:i], reference[i+1:]
+	}
+	pm, err := t.Manifest(path)
+	if err != nil {
+		return
+	}
+	if chain, err = pm.deploymentChain(blockchainuri); err != nil {
+		// The same chain is identified by the genesis hash alone
+		if i := strings.Index(blockchainuri, "/block/"); i >= 0 {
+			chain, err = pm.deploymentChain(blockchainuri[:i])
+		}
+		if err != nil {
+			err = fmt.Errorf("Package '%v' of '%v' has no deployments on '%v'", pm.PackageName, reference, blockchainuri)
+			return
+		}
+	}
+	ci, ok := pm.Deployments[chain][name]
+	if !ok || (ci == nil) {
+		err = fmt.Errorf("Package '%v' of '%v' has no contract instance '%v' on '%v'", pm.PackageName, reference, name, chain)
+	}
+	return
+}
+
+// readInstalled reads the manifest of the dependency at the given path from the
+// first install root holding it, nested under the 'ethpm-dependencies' directory
+// of each parent. A package of the same name installed elsewhere in the root may
+// be another package or version and is not used.
+func (t *DependencyTree) readInstalled(names []string) (pm *PackageManifest, err error) {
+	var candidates []string
+	for _, root := range t.InstallRoots {
+		nested := root
+		for _, name := range names {
+			nested = filepath.Join(nested, solcutils.DependencyDir, name)
+		}
+		candidates = append(candidates, filepath.Join(nested, "ethpm.json"))
+	}
+	path := strings.Join(names, ":")
+	for _, c := range candidates {
+		b, retErr := ioutil.ReadFile(c)
+		if os.IsNotExist(retErr) {
+			continue
+		}
+		if retErr != nil {
+			err = fmt.Errorf("Could not read manifest of '%v' at '%v': '%v'", path, c, retErr)
+			return
+		}
+		pm = &PackageManifest{}
+		if retErr = pm.Read(string(b)); retErr != nil {
+			err = fmt.Errorf("Could not parse manifest of '%v' at '%v': '%v'", path, c, retErr)
+		}
+		return
+	}
+	err = fmt.Errorf("Dependency '%v' is not installed in '%v'", path, strings.Join(t.InstallRoots, "', '"))
+	return
+}
@@ -0,0 +1,105 @@
+package ethpm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+)
+
+func TestDependencyTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chain := "blockchain://d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3/block/1e96de11320c83cca02e8b9caf3e489497e8e432befe5379f2f08599f8aecede"
+	other := "blockchain://d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3/block/752820c0ad7abc1200f9ad42c4adc6fbb4bd44b5bed4667990e64565102c1ba6"
+	// math is installed nested under the directory of its parent
+	mathdir := filepath.Join(dir, "ethpm-dependencies", "token", "ethpm-dependencies", "math")
+	if err = os.MkdirAll(mathdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The manifest is longer than any fixed size buffer
+	math := `{"manifest_version":"2","package_name":"math","version":"1.0.0","deployments":{"` + other +
+		`":{"SafeMath":{"address":"0x8d2c532d7d211816a2807a411f947b211569b68c","contract_type":"SafeMath"}}}}`
+	if err = ioutil.WriteFile(filepath.Join(mathdir, "ethpm.json"), []byte(math), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &PackageManifest{PackageName: "wallet"}
+	p.AddDependency("token", "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW")
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		chain: {"Wallet": {Address: "0x4f5b8f9b6a4f2ef1c0b81b5e9bd8e5a5c1f5c7b1", ContractType: "Wallet"}},
+	}
+	token := &PackageManifest{PackageName: "token"}
+	token.AddDependency("math", "ipfs://QmYxRT4k5ByUH47AHtvaq9DHqMdsWnMvChcWbWUCFdmkTV")
+
+	tree := NewDependencyTree(p, dir)
+	tree.AddManifest("token", token)
+
+	ci, got, err := tree.ResolveInstance(chain, "token:math:SafeMath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci.Address != "0x8d2c532d7d211816a2807a411f947b211569b68c" {
+		t.Fatalf("Got '%v', expected '%v'", ci.Address, "0x8d2c532d7d211816a2807a411f947b211569b68c")
+	}
+	if got != other {
+		t.Fatalf("Got '%v', expected '%v'", got, other)
+	}
+	if ci, _, err = tree.ResolveInstance(chain, "Wallet"); (err != nil) || (ci.ContractType != "Wallet") {
+		t.Fatalf("Got '%v', expected the Wallet instance", err)
+	}
+
+	_, _, err = tree.ResolveInstance(chain, "token:owned:Owned")
+	want := errors.New("No build dependency 'owned' in package 'token' while resolving 'token:owned'")
+	if (err == nil) || (err.Error() != want.Error()) {
+		t.Fatalf("Got '%v', expected '%v'", err, want)
+	}
+	_, _, err = tree.ResolveInstance(chain, "token:math:Missing")
+	want = errors.New("Package 'math' of 'token:math:Missing' has no contract instance 'Missing' on '" + other + "'")
+	if (err == nil) || (err.Error() != want.Error()) {
+		t.Fatalf("Got '%v', expected '%v'", err, want)
+	}
+
+	p.AddDependency("owned", "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW")
+	_, _, err = tree.ResolveInstance(chain, "owned:Owned")
+	want = errors.New("Dependency 'owned' is not installed in '" + dir + "'")
+	if (err == nil) || (err.Error() != want.Error()) {
+		t.Fatalf("Got '%v', expected '%v'", err, want)
+	}
+}
+
+func TestDependencyTreeNested(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// An unrelated math package is installed at the top level only
+	mathdir := filepath.Join(dir, "ethpm-dependencies", "math")
+	if err = os.MkdirAll(mathdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	math := `{"manifest_version":"2","package_name":"math","version":"2.0.0"}`
+	if err = ioutil.WriteFile(filepath.Join(mathdir, "ethpm.json"), []byte(math), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &PackageManifest{PackageName: "wallet"}
+	p.AddDependency("token", "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW")
+	token := &PackageManifest{PackageName: "token"}
+	token.AddDependency("math", "ipfs://QmYxRT4k5ByUH47AHtvaq9DHqMdsWnMvChcWbWUCFdmkTV")
+	tree := NewDependencyTree(p, dir)
+	tree.AddManifest("token", token)
+
+	_, err = tree.Manifest("token:math")
+	want := errors.New("Dependency 'token:math' is not installed in '" + dir + "'")
+	if (err == nil) || (err.Error() != want.Error()) {
+		t.Fatalf("Got '%v', expected '%v'", err, want)
+	}
+}
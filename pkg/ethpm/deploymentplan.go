@@ -0,0 +1,161 @@
+package ethpm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+// ContractDeployer is implemented by anything that can send contract creation
+// bytecode to a network. DeployContract takes the fully linked deployment bytecode
+// as a hex string and returns the address of the new contract along with the
+// transaction and block hashes. The block hash may be an empty string if it is
+// not known.
+type ContractDeployer interface {
+	DeployContract(bytecode string) (address string, transaction string, block string, err error)
+}
+
+// PlanDeployment takes a blockchain uri and the names of the contract types to
+// deploy, then returns the contract types in the order they need to be deployed.
+// Libraries referenced through link references are placed ahead of the contracts
+// that link to them and are left out entirely if they are already present in
+// this package's deployments for the given blockchain uri. An error is returned
+// if the link references form a cycle, reference a library that has neither a
+// contract type nor a deployment, or link libraries of the same name from
+// different sources, which cannot be told apart by contract type name.
+func (p *PackageManifest) PlanDeployment(blockchainuri string, contractnames ...string) (plan []string, err error) {
+	if err = checkLinkReferenceNames(p.ContractTypes); err != nil {
+		return
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	targets := make(map[string]bool)
+	for _, n := range contractnames {
+		targets[n] = true
+	}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("Link references form a cycle: '%v'", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+		if !targets[name] && p.Deployments[blockchainuri][name] != nil {
+			state[name] = visited
+			return nil
+		}
+		ct := p.ContractTypes[name]
+		if ct == nil {
+			if len(path) == 0 {
+				return fmt.Errorf("No contract type '%v' in package", name)
+			}
+			return fmt.Errorf("Library '%v' required by '%v' has no contract type and no deployment "+
+				"on '%v'", name, path[len(path)-1], blockchainuri)
+		}
+		state[name] = visiting
+		libs := linkedLibraries(ct)
+		for _, l := range sortedKeys(libs) {
+			if retErr := visit(libs[l], append(path, name)); retErr != nil {
+				return retErr
+			}
+		}
+		state[name] = visited
+		plan = append(plan, name)
+		return nil
+	}
+
+	for _, n := range contractnames {
+		if err = visit(n, nil); err != nil {
+			plan = nil
+			return
+		}
+	}
+	return
+}
+
+// DeployWithLibraries takes a blockchain uri, a ContractDeployer, and the names of
+// the contract types to deploy. It deploys every contract type in the order given
+// by PlanDeployment, linking each one against the addresses of the libraries it
+// references, and records every new instance in this package's deployments. Link
+// dependencies are recorded as 'reference' values pointing at the library instance.
+// The names of the newly deployed instances are returned in deployment order.
+func (p *PackageManifest) DeployWithLibraries(blockchainuri string, d ContractDeployer, contractnames ...string) (deployed []string, err error) {
+	plan, err := p.PlanDeployment(blockchainuri, contractnames...)
+	if err != nil {
+		err = fmt.Errorf("Could not plan deployment: '%v'", err)
+		return
+	}
+	for _, name := range plan {
+		ct := p.ContractTypes[name]
+		if (ct.DeploymentBytecode == nil) || (ct.DeploymentBytecode.Bytecode == "") {
+			err = fmt.Errorf("Contract type '%v' has no deployment_bytecode", name)
+			return
+		}
+		addresses := make(map[string]string)
+		for fullyqualifiedname, l := range linkedLibraries(ct) {
+			addresses[fullyqualifiedname] = p.Deployments[blockchainuri][l].Address
+		}
+		var depbytecode string
+		if depbytecode, err = ct.DeploymentBytecode.Link(addresses); err != nil {
+			err = fmt.Errorf("Could not link deployment_bytecode for '%v': '%v'", name, err)
+			return
+		}
+		info := &ethcontract.DeployedContractInfo{
+			ContractName: name,
+			CT:           ct,
+		}
+		if (ct.RuntimeBytecode != nil) && (ct.RuntimeBytecode.Bytecode != "") {
+			if info.BC, err = ct.RuntimeBytecode.Link(addresses); err != nil {
+				err = fmt.Errorf("Could not link runtime_bytecode for '%v': '%v'", name, err)
+				return
+			}
+			for _, lr := range ct.RuntimeBytecode.LinkReferences {
+				lv := &liblink.LinkValue{}
+				lv.Build("reference", lr.Name, lr.Offsets)
+				info.AddLinkReference(lr)
+				info.AddLinkValue(lv)
+			}
+		}
+		if info.Address, info.Transaction, info.Block, err = d.DeployContract(depbytecode); err != nil {
+			err = fmt.Errorf("Could not deploy '%v': '%v'", name, err)
+			return
+		}
+		p.AddDeployment(blockchainuri, info)
+		deployed = append(deployed, name)
+	}
+	return
+}
+
+// linkedLibraries returns the names of the libraries referenced by the link
+// references of a contract type, keyed by their fully qualified names
+func linkedLibraries(ct *ethcontract.ContractType) (libs map[string]string) {
+	libs = make(map[string]string)
+	if ct.DeploymentBytecode != nil {
+		for _, lr := range ct.DeploymentBytecode.LinkReferences {
+			libs[lr.FullyQualifiedName()] = lr.Name
+		}
+	}
+	if ct.RuntimeBytecode != nil {
+		for _, lr := range ct.RuntimeBytecode.LinkReferences {
+			libs[lr.FullyQualifiedName()] = lr.Name
+		}
+	}
+	return
+}
+
+func sortedKeys(m map[string]string) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
@@ -0,0 +1,143 @@
+package ethpm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
+)
+
+const testChain = "blockchain://41941023680923e0fe4d74a34bdac8141f2540e3ae90623718e47d66d1ca4a2d/block/1e96de11320c83cca02e8b9caf3e489497e8e432befe5379f2f08599f8aecede"
+
+type testDeployer struct {
+	deployed []string
+}
+
+func (d *testDeployer) DeployContract(bytecode string) (address string, transaction string, block string, err error) {
+	d.deployed = append(d.deployed, bytecode)
+	n := len(d.deployed)
+	address = fmt.Sprintf("0x%040x", n)
+	transaction = fmt.Sprintf("0x%064x", n)
+	block = fmt.Sprintf("0x%064x", n+100)
+	return
+}
+
+func linkedContractType(libs ...string) *ethcontract.ContractType {
+	ct := &ethcontract.ContractType{}
+	ct.DeploymentBytecode = &bc.UnlinkedBytecode{}
+	ct.RuntimeBytecode = &bc.UnlinkedBytecode{}
+	code := "60"
+	for i, l := range libs {
+		// Libraries are given by name or fully qualified, as "source:Name"
+		source, name := "", l
+		if j := strings.LastIndex(l, ":"); j >= 0 {
+			source, name = l[:j], l[j+1:]
+		}
+		lr := &liblink.LinkReference{}
+		lr.Build(source, name, []map[string]int{{"length": 20, "start": 1 + (i * 21)}})
+		ct.DeploymentBytecode.LinkReferences = append(ct.DeploymentBytecode.LinkReferences, lr)
+		ct.RuntimeBytecode.LinkReferences = append(ct.RuntimeBytecode.LinkReferences, lr)
+		code += strings.Repeat("00", 20) + "73"
+	}
+	ct.DeploymentBytecode.Bytecode = "0x" + code + "f3"
+	ct.RuntimeBytecode.Bytecode = "0x" + code + "00"
+	return ct
+}
+
+func TestPlanDeployment(t *testing.T) {
+	p := PackageManifest{}
+	p.ContractTypes = map[string]*ethcontract.ContractType{
+		"BasicMathLib": linkedContractType(),
+		"TokenLib":     linkedContractType("BasicMathLib"),
+		"Token":        linkedContractType("TokenLib", "BasicMathLib"),
+	}
+
+	got, err := p.PlanDeployment(testChain, "Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "BasicMathLib,TokenLib,Token"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		testChain: {"BasicMathLib": &ethcontract.ContractInstance{}},
+	}
+	got, err = p.PlanDeployment(testChain, "Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "TokenLib,Token"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	p.ContractTypes["BasicMathLib"] = linkedContractType("Token")
+	p.Deployments = nil
+	_, err = p.PlanDeployment(testChain, "Token")
+	wantErr := errors.New("Link references form a cycle: 'Token -> BasicMathLib -> Token'")
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("Got '%v', expected '%v'", err, wantErr)
+	}
+
+	p.ContractTypes["BasicMathLib"] = linkedContractType()
+	p.ContractTypes["Token"] = linkedContractType("a/Math.sol:BasicMathLib", "b/Math.sol:BasicMathLib")
+	if _, err = p.PlanDeployment(testChain, "Token"); err == nil {
+		t.Fatal("Got <nil>, expected an error for libraries of the same name from different sources")
+	}
+
+	delete(p.ContractTypes, "Token")
+	delete(p.ContractTypes, "BasicMathLib")
+	_, err = p.PlanDeployment(testChain, "TokenLib")
+	wantErr = errors.New("Library 'BasicMathLib' required by 'TokenLib' has no contract type and no deployment on '" +
+		testChain + "'")
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("Got '%v', expected '%v'", err, wantErr)
+	}
+}
+
+func TestDeployWithLibraries(t *testing.T) {
+	p, err := CreateNewManifest("token", "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ContractTypes = map[string]*ethcontract.ContractType{
+		"BasicMathLib": linkedContractType(),
+		"TokenLib":     linkedContractType("contracts/Math.sol:BasicMathLib"),
+	}
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		testChain: {"BasicMathLib": &ethcontract.ContractInstance{
+			Address:      "0x" + strings.Repeat("ab", 20),
+			ContractType: "BasicMathLib",
+		}},
+	}
+	d := &testDeployer{}
+
+	got, err := p.DeployWithLibraries(testChain, d, "TokenLib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "TokenLib" {
+		t.Fatalf("Got '%v', expected '[TokenLib]'", got)
+	}
+	if want := "0x60" + strings.Repeat("ab", 20) + "73f3"; d.deployed[0] != want {
+		t.Fatalf("Got '%v', expected '%v'", d.deployed[0], want)
+	}
+
+	ci := p.Deployments[testChain]["TokenLib"]
+	if ci.Address != fmt.Sprintf("0x%040x", 1) {
+		t.Fatalf("Got '%v', expected '%v'", ci.Address, fmt.Sprintf("0x%040x", 1))
+	}
+	lv := ci.RuntimeBytecode.LinkDependencies[0]
+	if (lv.Type != "reference") || (lv.Value != "BasicMathLib") || (lv.Offsets[0] != 1) {
+		t.Fatalf("Got '%+v', expected a reference to BasicMathLib at offset 1", lv)
+	}
+	if err = p.Validate(); err != nil {
+		t.Fatalf("Got '%v', expected <nil>", err)
+	}
+}
@@ -0,0 +1,205 @@
+package ethpm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/ethregexlib"
+	"github.com/ethpm/ethpm-go/pkg/githubutils"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// ExtractSources writes every source of the package to its relative path in the
+// target directory, and the sources of its build dependencies, along with their
+// manifests, under 'ethpm-dependencies' as they are laid out when installed, so
+// the package can be recompiled or audited. Sources are read as VerifySources
+// does, relative to manifestdir, and checked against their checksums if they
+// were recorded. Dependency manifests are read from manifestdir if they are
+// installed there, otherwise they are fetched from their uri. Sources whose path
+// escapes the target directory, and dependencies whose name is not a valid
+// package name, are refused. Dependency sources may not read outside their
+// directory, and fetched dependency manifests may not read local files at all.
+// It returns the slash separated paths written, relative to the target
+// directory.
+func (p *PackageManifest) ExtractSources(targetdir string, manifestdir string, gateway *solcutils.MetadataGateway) (written []string, err error) {
+	if gateway == nil {
+		gateway = solcutils.NewMetadataGateway("", "")
+	}
+	if err = p.extract(targetdir, manifestdir, gateway, githubutils.Repositories{}, "", false, &written); err != nil {
+		return
+	}
+	sort.Strings(written)
+	return
+}
+
+// extract writes the sources of the package to dir and recurses into its build
+// dependencies. The prefix is the path of dir relative to the target directory,
+// empty for the package itself, and fetched is true if the manifest was fetched
+// from its uri rather than read from manifestdir. Git repositories are opened
+// once through repos for the whole extraction.
+func (p *PackageManifest) extract(dir string, manifestdir string, gateway *solcutils.MetadataGateway, repos githubutils.Repositories, prefix string, fetched bool, written *[]string) (err error) {
+	keys := make([]string, 0, len(p.Sources))
+	for k := range p.Sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		location, retErr := extractPath(dir, k)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		if prefix != "" {
+			if err = checkDependencySource(p.Sources[k], manifestdir, fetched); err != nil {
+				err = fmt.Errorf("Source '%v': %v", prefix+k, err)
+				return
+			}
+		}
+		content, retErr := fetchSource(p.Sources[k], manifestdir, gateway, repos)
+		if retErr != nil {
+			err = fmt.Errorf("Could not fetch source '%v': '%v'", prefix+k, retErr)
+			return
+		}
+		if c, ok := p.SourceChecksums[k]; ok {
+			if retErr = c.Verify(content); retErr != nil {
+				err = fmt.Errorf("Source '%v' does not match: '%v'", prefix+k, retErr)
+				return
+			}
+		}
+		if err = writeFile(location, content); err != nil {
+			return
+		}
+		rel, _ := filepath.Rel(dir, location)
+		*written = append(*written, prefix+filepath.ToSlash(rel))
+	}
+	for _, name := range p.dependencyNames() {
+		if retErr := ethregexlib.CheckPackageName(name); retErr != nil {
+			err = fmt.Errorf("Invalid dependency name '%v': '%v'", name, retErr)
+			return
+		}
+		target, retErr := extractPath(filepath.Join(dir, solcutils.DependencyDir), name)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		dep, depdir, depfetched, retErr := p.dependencyManifest(name, manifestdir, gateway, repos)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		manifest, retErr := dep.Write()
+		if retErr != nil {
+			err = fmt.Errorf("Could not write manifest of dependency '%v': '%v'", name, retErr)
+			return
+		}
+		if err = writeFile(filepath.Join(target, "ethpm.json"), []byte(manifest)); err != nil {
+			return
+		}
+		deprefix := prefix + solcutils.DependencyDir + "/" + name + "/"
+		*written = append(*written, deprefix+"ethpm.json")
+		if err = dep.extract(target, depdir, gateway, repos, deprefix, depfetched, written); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// dependencyManifest returns the manifest of a build dependency, installed in
+// manifestdir, with the checksums recorded next to it, or fetched from its uri,
+// along with the directory its local sources are relative to and whether it was
+// fetched. Fetched ipfs and GitHub blob manifests are checked against the hash
+// in their uri.
+func (p *PackageManifest) dependencyManifest(name string, manifestdir string, gateway *solcutils.MetadataGateway, repos githubutils.Repositories) (dep *PackageManifest, depdir string, fetched bool, err error) {
+	depdir = filepath.Join(manifestdir, solcutils.DependencyDir, name)
+	if dep, err = readInstalledManifest(manifestdir, name); err == nil {
+		if _, retErr := os.Stat(filepath.Join(depdir, ChecksumFile)); retErr == nil {
+			err = dep.ReadChecksums(depdir)
+		}
+		return
+	} else if !os.IsNotExist(err) {
+		err = fmt.Errorf("Could not read installed dependency '%v': '%v'", name, err)
+		return
+	}
+	fetched = true
+	uri := p.BuildDependencies[name]
+	b, err := fetchSource(uri, manifestdir, gateway, repos)
+	if err != nil {
+		err = fmt.Errorf("Could not fetch manifest of dependency '%v': '%v'", name, err)
+		return
+	}
+	for _, c := range uriChecksums(uri) {
+		if err = c.Verify(b); err != nil {
+			err = fmt.Errorf("Manifest of dependency '%v' does not match: '%v'", name, err)
+			return
+		}
+	}
+	dep = &PackageManifest{}
+	if err = dep.Read(string(b)); err != nil {
+		err = fmt.Errorf("Could not parse manifest of dependency '%v': '%v'", name, err)
+	}
+	return
+}
+
+// checkDependencySource returns an error for a source value of a dependency
+// manifest which reads from outside the dependency. Relative paths may not
+// escape depdir, and a fetched manifest, which is untrusted, may only inline its
+// sources or point to http, https, ipfs or remote git uris.
+func checkDependencySource(value string, depdir string, fetched bool) (err error) {
+	if !isSourceLocation(value) {
+		return
+	}
+	if uri, retErr := url.Parse(value); (retErr == nil) && uri.IsAbs() {
+		if !fetched {
+			return
+		}
+		switch uri.Scheme {
+		case "http", "https", "ipfs":
+			return
+		case "git", "ssh":
+			if githubutils.IsGitURI(value) {
+				return
+			}
+		}
+		err = fmt.Errorf("Uri '%v' of a fetched manifest is not a remote uri", value)
+		return
+	}
+	if fetched {
+		err = fmt.Errorf("Path '%v' of a fetched manifest is a local path", value)
+		return
+	}
+	_, err = extractPath(depdir, value)
+	return
+}
+
+// extractPath returns the location of a source key in dir, refusing keys which
+// are absolute or escape dir
+func extractPath(dir string, key string) (location string, err error) {
+	p := filepath.FromSlash(key)
+	if filepath.IsAbs(p) || (filepath.VolumeName(p) != "") || strings.HasPrefix(key, "/") {
+		err = fmt.Errorf("Source '%v' is not a relative path", key)
+		return
+	}
+	location = filepath.Join(dir, p)
+	rel, err := filepath.Rel(dir, location)
+	if (err != nil) || (rel == ".") || (rel == "..") || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		err = fmt.Errorf("Source '%v' escapes the target directory", key)
+	}
+	return
+}
+
+// writeFile writes content to the location, creating its directory
+func writeFile(location string, content []byte) (err error) {
+	if err = os.MkdirAll(filepath.Dir(location), os.ModePerm); err != nil {
+		err = fmt.Errorf("Could not create directory for '%v': '%v'", location, err)
+		return
+	}
+	if err = ioutil.WriteFile(location, content, 0644); err != nil {
+		err = fmt.Errorf("Could not write '%v': '%v'", location, err)
+	}
+	return
+}
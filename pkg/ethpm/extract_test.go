@@ -0,0 +1,158 @@
+package ethpm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+func TestExtractSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manifestdir := filepath.Join(dir, "package")
+	files := map[string]string{
+		"contracts/Token.sol":                          "contract Token {}\n",
+		"ethpm-dependencies/owned/ethpm.json":          `{"manifest_version":"2","package_name":"owned","version":"1.0.0","sources":{"./contracts/Owned.sol":"./contracts/Owned.sol"}}`,
+		"ethpm-dependencies/owned/contracts/Owned.sol": "contract Owned {}\n",
+	}
+	writeTestFiles(t, manifestdir, files)
+	p := &PackageManifest{
+		Sources: map[string]string{
+			"./contracts/Token.sol":    "./contracts/Token.sol",
+			"./contracts/SafeMath.sol": "library SafeMath {}\n",
+		},
+		BuildDependencies: map[string]string{"owned": "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW"},
+	}
+	if err = p.AddSourceChecksum("./contracts/Token.sol", []byte("contract Token {}\n"), ChecksumKeccak256); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(dir, "out")
+	written, err := p.ExtractSources(target, manifestdir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"contracts/SafeMath.sol",
+		"contracts/Token.sol",
+		"ethpm-dependencies/owned/contracts/Owned.sol",
+		"ethpm-dependencies/owned/ethpm.json",
+	}
+	if !reflect.DeepEqual(written, expected) {
+		t.Fatalf("Got '%v', expected '%v'", written, expected)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(target, "ethpm-dependencies", "owned", "contracts", "Owned.sol"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "contract Owned {}\n" {
+		t.Fatalf("Got '%v', expected '%v'", string(b), "contract Owned {}\n")
+	}
+
+	p.SourceChecksums["./contracts/Token.sol"].Hash = "0x00"
+	if _, err = p.ExtractSources(target, manifestdir, nil); err == nil {
+		t.Fatalf("Got '%v', expected an error for a source not matching its checksum", err)
+	}
+	escaping := &PackageManifest{Sources: map[string]string{"./../escaped.sol": "contract Escaped {}\n"}}
+	if _, err = escaping.ExtractSources(target, manifestdir, nil); err == nil {
+		t.Fatalf("Got '%v', expected an error for a source escaping the target directory", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "escaped.sol")); !os.IsNotExist(err) {
+		t.Fatalf("Got '%v', expected the escaping source not to be written", err)
+	}
+	escaping = &PackageManifest{BuildDependencies: map[string]string{"../../evil": "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW"}}
+	if _, err = escaping.ExtractSources(target, manifestdir, nil); (err == nil) || !strings.Contains(err.Error(), "Invalid dependency name") {
+		t.Fatalf("Got '%v', expected an error for an invalid dependency name", err)
+	}
+	missing := &PackageManifest{Sources: map[string]string{"./contracts/Missing.sol": "./contracts/Missing.sol"}}
+	if _, err = missing.ExtractSources(target, manifestdir, nil); (err == nil) || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("Got '%v', expected an error for a missing source", err)
+	}
+}
+
+func TestExtractSourcesFetchedDependency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manifest := `{"manifest_version":"2","package_name":"owned","version":"1.0.0","sources":{"./Owned.sol":"contract Owned {}\n"}}`
+	cid, err := solcutils.IPFSHash([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := manifest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/"+cid {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(served))
+	}))
+	defer srv.Close()
+	gateway := solcutils.NewMetadataGateway(srv.URL, "")
+
+	p := &PackageManifest{BuildDependencies: map[string]string{"owned": "ipfs://" + cid}}
+	written, err := p.ExtractSources(filepath.Join(dir, "out"), dir, gateway)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ethpm-dependencies/owned/Owned.sol", "ethpm-dependencies/owned/ethpm.json"}
+	if !reflect.DeepEqual(written, expected) {
+		t.Fatalf("Got '%v', expected '%v'", written, expected)
+	}
+
+	served = strings.Replace(manifest, "Owned {}", "Owned { function tampered() {} }", 1)
+	if _, err = p.ExtractSources(filepath.Join(dir, "tampered"), dir, gateway); (err == nil) || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("Got '%v', expected an error for a tampered dependency manifest", err)
+	}
+
+	// Fetched manifests may not read local files
+	secret := filepath.Join(dir, "ethpm-dependencies", "secret")
+	writeTestFiles(t, dir, map[string]string{"ethpm-dependencies/secret": "secret"})
+	for _, value := range []string{"../secret", "file://" + filepath.ToSlash(secret)} {
+		served = `{"manifest_version":"2","package_name":"owned","version":"1.0.0","sources":{"./Owned.sol":"` + value + `"}}`
+		if cid, err = solcutils.IPFSHash([]byte(served)); err != nil {
+			t.Fatal(err)
+		}
+		p.BuildDependencies["owned"] = "ipfs://" + cid
+		untrusted := filepath.Join(dir, "untrusted")
+		if _, err = p.ExtractSources(untrusted, dir, gateway); (err == nil) || !strings.Contains(err.Error(), "of a fetched manifest") {
+			t.Fatalf("Got '%v', expected an error for the local source '%v' of a fetched manifest", err, value)
+		}
+		if _, err = os.Stat(filepath.Join(untrusted, "ethpm-dependencies", "owned", "Owned.sol")); !os.IsNotExist(err) {
+			t.Fatalf("Got '%v', expected the local source '%v' not to be written", err, value)
+		}
+	}
+}
+
+func TestCheckDependencySource(t *testing.T) {
+	tests := []struct {
+		value   string
+		fetched bool
+		valid   bool
+	}{
+		{"contract Owned {}\n", true, true},
+		{"ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW", true, true},
+		{"https://api.github.com/repos/ethpm/owned/git/blobs/3b18e512dba79e4c8300dd08aeb37f8e728b8dad", true, true},
+		{"./contracts/Owned.sol", true, false},
+		{"file:///etc/passwd", true, false},
+		{"./contracts/Owned.sol", false, true},
+		{"../../secret", false, false},
+	}
+	for _, tt := range tests {
+		err := checkDependencySource(tt.value, "ethpm-dependencies/owned", tt.fetched)
+		if (err == nil) != tt.valid {
+			t.Fatalf("Got '%v' for '%v', expected valid to be '%v'", err, tt.value, tt.valid)
+		}
+	}
+}
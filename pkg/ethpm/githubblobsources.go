@@ -0,0 +1,28 @@
+package ethpm
+
+import (
+	"net/url"
+
+	"github.com/ethpm/ethpm-go/pkg/githubutils"
+)
+
+// AddGithubBlobURIs takes the GitHub account and repository the project is
+// committed to and the project directory, which can be empty for the current
+// working directory. Every local source, either a path relative to the project
+// directory or inlined content, is replaced with the GitHub api blob uri of its
+// content, whose hash is computed locally. Paths which do not exist are an
+// error. Sources at a uri are left as is.
+func (p *PackageManifest) AddGithubBlobURIs(account string, repo string, projectdir string) (err error) {
+	for k, v := range p.Sources {
+		if uri, retErr := url.Parse(v); (retErr == nil) && uri.IsAbs() {
+			continue
+		}
+		content, retErr := sourceContent(projectdir, k, v)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		p.Sources[k] = githubutils.CreateGithubBlobURI(account, repo, []byte(content))
+	}
+	return
+}
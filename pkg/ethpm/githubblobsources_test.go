@@ -0,0 +1,70 @@
+package ethpm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethpm/ethpm-go/pkg/githubutils"
+)
+
+func TestAddGithubBlobURIs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	owned := "contract Owned {}\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "Owned.sol"), []byte(owned), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := &PackageManifest{Sources: map[string]string{
+		"./Owned.sol": "./Owned.sol",
+		"./Token.sol": "contract Token {}\n",
+		"./Hello.txt": "ipfs://QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+	}}
+	if err = p.AddGithubBlobURIs("ethpm", "token", dir); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"./Owned.sol": githubutils.CreateGithubBlobURI("ethpm", "token", []byte(owned)),
+		"./Token.sol": githubutils.CreateGithubBlobURI("ethpm", "token", []byte("contract Token {}\n")),
+		"./Hello.txt": "ipfs://QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+	}
+	for k, v := range want {
+		if p.Sources[k] != v {
+			t.Fatalf("Got '%v' for '%v', expected '%v'", p.Sources[k], k, v)
+		}
+	}
+
+	missing := &PackageManifest{Sources: map[string]string{"./Missing.sol": "./Missing.sol"}}
+	if err = missing.AddGithubBlobURIs("ethpm", "token", dir); (err == nil) || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("Got '%v', expected an error for a missing source", err)
+	}
+
+	served := owned
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"content":  base64.StdEncoding.EncodeToString([]byte(served)),
+			"encoding": "base64",
+		})
+	}))
+	defer srv.Close()
+	// Blob uris are verified against their hash without a recorded checksum
+	blob := &PackageManifest{Sources: map[string]string{
+		"./Owned.sol": srv.URL + "/repos/ethpm/token/git/blobs/" + githubutils.GitBlobHash([]byte(owned)),
+	}}
+	if err = blob.VerifySources(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	served = "contract Tampered {}\n"
+	if err = blob.VerifySources(dir, nil); (err == nil) || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("Got '%v', expected an error for a tampered blob", err)
+	}
+}
@@ -0,0 +1,201 @@
+package ethpm
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/gethutils"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// ContractHandle is a deployed contract instance of a package bound to the abi
+// of its contract type. Arguments are given as strings and parsed according to
+// the abi. If Backend is nil, the ipc connection of a geth node using the default
+// data directory is used.
+type ContractHandle struct {
+	ABI          abi.ABI
+	Address      common.Address
+	Backend      bind.ContractBackend
+	ContractType *ethcontract.ContractType
+	Name         string
+}
+
+// Instance takes a blockchain uri, or a prefix matching exactly one blockchain
+// uri of the deployments, and the name of a contract instance deployed on it,
+// then returns a handle for calling it. References to contract types of build
+// dependencies, such as "owned:Owned", are resolved through the dependencies
+// installed in the project directory, which can be empty for the working
+// directory.
+func (p *PackageManifest) Instance(blockchainuri string, name string, projectdir string) (h *ContractHandle, err error) {
+	chain, err := p.deploymentChain(blockchainuri)
+	if err != nil {
+		return
+	}
+	ci, ok := p.Deployments[chain][name]
+	if !ok {
+		err = fmt.Errorf("No deployment '%v' on '%v'", name, chain)
+		return
+	}
+	ct, err := p.ResolveContractType(ci.ContractType, projectdir)
+	if err != nil {
+		return
+	}
+	gethabi, err := ethcontract.ToGethABI(ct.ABI)
+	if err != nil {
+		err = fmt.Errorf("Error reading abi of '%v': '%v'", ci.ContractType, err)
+		return
+	}
+	h = &ContractHandle{
+		ABI:          gethabi,
+		Address:      common.HexToAddress(ci.Address),
+		ContractType: ct,
+		Name:         name,
+	}
+	return
+}
+
+// ResolveContractType takes a contract type reference of a contract instance,
+// either the name of a contract type in this package or a build dependency and
+// a reference into it separated by a colon, such as "owned:Owned", and the
+// project directory containing the installed build dependencies, which can be
+// empty for the working directory. It returns the referenced contract type.
+func (p *PackageManifest) ResolveContractType(reference string, projectdir string) (ct *ethcontract.ContractType, err error) {
+	parts := strings.SplitN(reference, ":", 2)
+	if len(parts) == 1 {
+		var ok bool
+		if ct, ok = p.ContractTypes[reference]; !ok {
+			err = fmt.Errorf("No contract type '%v' in package '%v'", reference, p.PackageName)
+		}
+		return
+	}
+	if _, ok := p.BuildDependencies[parts[0]]; !ok {
+		err = fmt.Errorf("No build dependency '%v' in package '%v'", parts[0], p.PackageName)
+		return
+	}
+	dep, err := readInstalledManifest(projectdir, parts[0])
+	if err != nil {
+		err = fmt.Errorf("Could not read installed dependency '%v': '%v'", parts[0], err)
+		return
+	}
+	return dep.ResolveContractType(parts[1], filepath.Join(projectdir, solcutils.DependencyDir, parts[0]))
+}
+
+// IsConstant returns true if the method does not modify state and is called
+// rather than sent as a transaction, as recorded in the abi of the contract type
+func (h *ContractHandle) IsConstant(method string) bool {
+	for _, a := range h.ContractType.ABI {
+		if ((a.Type == "function") || (a.Type == "")) && (a.Name == method) {
+			return a.IsConstant()
+		}
+	}
+	return false
+}
+
+// Call calls a constant method of the contract with arguments given as strings
+// and returns the decoded results
+func (h *ContractHandle) Call(ctx context.Context, method string, args ...string) (results []interface{}, err error) {
+	m, input, err := h.pack(method, args)
+	if err != nil {
+		return
+	}
+	backend, err := h.backend()
+	if err != nil {
+		return
+	}
+	output, err := backend.CallContract(ctx, ethereum.CallMsg{To: &h.Address, Data: input}, nil)
+	if err != nil {
+		err = fmt.Errorf("Error calling '%v': '%v'", method, err)
+		return
+	}
+	if (len(output) == 0) && (len(m.Outputs) > 0) {
+		err = fmt.Errorf("Empty result calling '%v', is '%v' a contract?", method, h.Address.Hex())
+		return
+	}
+	if results, err = m.Outputs.UnpackValues(output); err != nil {
+		err = fmt.Errorf("Error decoding results of '%v': '%v'", method, err)
+	}
+	return
+}
+
+// Transact sends a transaction calling a method of the contract, with arguments
+// given as strings, signed with the given options. It does not wait for the
+// transaction to be mined.
+func (h *ContractHandle) Transact(opts *bind.TransactOpts, method string, args ...string) (tx *types.Transaction, err error) {
+	m, _, err := h.pack(method, args)
+	if err != nil {
+		return
+	}
+	parsed, _ := ethcontract.ParseArguments(m.Inputs, args)
+	backend, err := h.backend()
+	if err != nil {
+		return
+	}
+	c := bind.NewBoundContract(h.Address, h.ABI, backend, backend, backend)
+	if tx, err = c.Transact(opts, method, parsed...); err != nil {
+		err = fmt.Errorf("Error sending '%v' transaction: '%v'", method, err)
+	}
+	return
+}
+
+// pack returns the method and its call data for arguments given as strings
+func (h *ContractHandle) pack(method string, args []string) (m abi.Method, input []byte, err error) {
+	m, ok := h.ABI.Methods[method]
+	if !ok {
+		err = fmt.Errorf("No method '%v' in contract '%v'", method, h.Name)
+		return
+	}
+	parsed, err := ethcontract.ParseArguments(m.Inputs, args)
+	if err != nil {
+		err = fmt.Errorf("Invalid arguments for '%v': '%v'", method, err)
+		return
+	}
+	if input, err = h.ABI.Pack(method, parsed...); err != nil {
+		err = fmt.Errorf("Error encoding arguments for '%v': '%v'", method, err)
+	}
+	return
+}
+
+// backend returns the Backend, connecting to geth if it has not been set
+func (h *ContractHandle) backend() (backend bind.ContractBackend, err error) {
+	if h.Backend == nil {
+		ec, _, retErr := gethutils.ConnectGeth(node.DefaultDataDir())
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		h.Backend = ec
+	}
+	backend = h.Backend
+	return
+}
+
+// deploymentChain returns the blockchain uri of the deployments matching the
+// given uri exactly or, failing that, as the only uri it is a prefix of
+func (p *PackageManifest) deploymentChain(blockchainuri string) (chain string, err error) {
+	if _, ok := p.Deployments[blockchainuri]; ok {
+		chain = blockchainuri
+		return
+	}
+	for k := range p.Deployments {
+		if strings.HasPrefix(k, blockchainuri) {
+			if chain != "" {
+				err = fmt.Errorf("Blockchain uri '%v' matches more than one deployment chain", blockchainuri)
+				return
+			}
+			chain = k
+		}
+	}
+	if chain == "" {
+		err = fmt.Errorf("No deployments on '%v'", blockchainuri)
+	}
+	return
+}
@@ -0,0 +1,130 @@
+package ethpm
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+)
+
+// answerABI describes a contract whose code returns 42 for every call
+const answerABI = `[{"constant":true,"inputs":[{"name":"question","type":"uint256"}],"name":"answer",` +
+	`"outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},` +
+	`{"constant":false,"inputs":[{"name":"question","type":"uint256"}],"name":"ask","outputs":[],` +
+	`"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+// answerBin copies and returns the runtime code 602a60005260206000f3, which
+// stores 42 in memory and returns it
+const answerBin = "0x600a600c600039600a6000f3602a60005260206000f3"
+
+func TestInstance(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	opts := bind.NewKeyedTransactor(key)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{opts.From: {Balance: big.NewInt(1000000000000000000)}}, 4712388)
+	address, _, _, err := bind.DeployContract(opts, abi.ABI{}, common.FromHex(answerBin), sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	abis, err := ethcontract.ParseABI(answerABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &PackageManifest{PackageName: "answer"}
+	p.ContractTypes = map[string]*ethcontract.ContractType{"Answer": {ABI: abis}}
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		testChain: {"TheAnswer": {Address: address.Hex(), ContractType: "Answer"}},
+	}
+
+	h, err := p.Instance(testChain[:len("blockchain://")+16], "TheAnswer", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Backend = sim
+	results, err := h.Call(context.Background(), "answer", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ethcontract.FormatValue(results[0]); got != "42" {
+		t.Fatalf("Got '%v', expected '42'", got)
+	}
+	if _, err = h.Call(context.Background(), "answer", "-7"); err == nil {
+		t.Fatal("Got <nil>, expected an error for a negative uint256")
+	}
+	if _, err = h.Transact(opts, "ask", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsConstant("answer") || h.IsConstant("ask") {
+		t.Fatal("Got wrong constant methods, expected only 'answer'")
+	}
+	if _, err = p.Instance(testChain, "Missing", ""); err == nil {
+		t.Fatal("Got <nil>, expected an error for a missing deployment")
+	}
+}
+
+func TestInstanceStateMutability(t *testing.T) {
+	abis, err := ethcontract.ParseABI(`[{"inputs":[{"name":"question","type":"uint256"}],"name":"answer",` +
+		`"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &PackageManifest{PackageName: "answer"}
+	p.ContractTypes = map[string]*ethcontract.ContractType{"Answer": {ABI: abis}}
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		testChain: {"TheAnswer": {Address: "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", ContractType: "Answer"}},
+	}
+	h, err := p.Instance(testChain, "TheAnswer", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsConstant("answer") || !h.ABI.Methods["answer"].Const {
+		t.Fatal("Got a transaction, expected view function 'answer' to be called")
+	}
+}
+
+func TestResolveContractType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	depdir := filepath.Join(dir, "ethpm-dependencies", "owned")
+	if err = os.MkdirAll(depdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dep := `{"manifest_version":"2","package_name":"owned","version":"1.0.0","contract_types":{"Owned":{"abi":[]}}}`
+	if err = ioutil.WriteFile(filepath.Join(depdir, "ethpm.json"), []byte(dep), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := &PackageManifest{PackageName: "token"}
+	p.AddDependency("owned", "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW")
+
+	ct, err := p.ResolveContractType("owned:Owned", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct == nil {
+		t.Fatal("Got <nil>, expected the contract type of the dependency")
+	}
+	if _, err = p.ResolveContractType("missing:Owned", dir); err == nil {
+		t.Fatal("Got <nil>, expected an error for an unknown dependency")
+	}
+
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		testChain: {"Owner": {Address: "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", ContractType: "owned:Owned"}},
+	}
+	if h, err := p.Instance(testChain, "Owner", dir); (err != nil) || (h.ContractType == nil) {
+		t.Fatalf("Got '%v', expected the instance to resolve through the project directory", err)
+	}
+}
@@ -1,6 +1,13 @@
 package ethpm
 
-import "github.com/ethpm/ethpm-go/pkg/ethcontract"
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
 
 // ManifestInterface The interface for an ethpm PackageManifest type
 type ManifestInterface interface {
@@ -12,6 +19,14 @@ type ManifestInterface interface {
 	AddDeployment(blockchainuri string, d *ethcontract.DeployedContractInfo)
 	SourceInliner(contractdir string, sourcerelativepath string, sourcetype string) (err error)
 	AddLocalPathForSource(contractdir string, sourcerelativepath string, sourcetype string) (err error)
+	AddSources(projectdir string, sc *SourceCollector, inline bool) (err error)
+	AddSourceChecksum(key string, content []byte, algorithm string) (err error)
+	ReadChecksums(directoryname string) (err error)
+	WriteChecksums(directoryname string) (err error)
+	VerifySources(manifestdir string, gateway *solcutils.MetadataGateway) (err error)
+	AddGithubBlobURIs(account string, repo string, projectdir string) (err error)
+	ExtractSources(targetdir string, manifestdir string, gateway *solcutils.MetadataGateway) (written []string, err error)
+	VerifyContractTypes(manifestdir string, gateway *solcutils.MetadataGateway, manager *solcutils.SolcManager) (verdicts []*ContractTypeVerdict, err error)
 	CompileAndValidateSource(compiler string,
 		projectdir string,
 		contractname string,
@@ -19,7 +34,24 @@ type ManifestInterface interface {
 		filepath string,
 		optimize bool,
 		runs int,
-	) (valid bool, producedobject string, err error)
+		manager *solcutils.SolcManager,
+	) (valid bool, match bc.MatchMode, producedobject string, err error)
+	CompileProject(compiler string, projectdir string, settings *solcutils.SolcSettings) (stdinjson string, stdoutjson string, err error)
+	PlanDeployment(blockchainuri string, contractnames ...string) (plan []string, err error)
+	DeployWithLibraries(blockchainuri string, d ContractDeployer, contractnames ...string) (deployed []string, err error)
+	Instance(blockchainuri string, name string, projectdir string) (h *ContractHandle, err error)
+	VerifyDeployment(ctx context.Context,
+		blockchainuri string,
+		name string,
+		projectdir string,
+		backend bind.ContractCaller,
+	) (v *DeploymentVerification, err error)
+	ResolveContractType(reference string, projectdir string) (ct *ethcontract.ContractType, err error)
+	NewDecoder(projectdir string) (d *Decoder, err error)
+	MetadataInput(contractname string,
+		projectdir string,
+		gateway *solcutils.MetadataGateway,
+	) (cm *solcutils.CompilerMetadata, stdinjson string, err error)
 	PublishToRepositoryWithPassword(repositoryaddressashex string,
 		manifesturi string,
 		fromaddressashex string,
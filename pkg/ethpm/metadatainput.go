@@ -0,0 +1,64 @@
+package ethpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// MetadataInput takes the name of a contract type, the project directory, which
+// can be empty for the working directory, and the gateway to fetch metadata
+// from, which can be nil for the default gateways. It fetches the compiler
+// metadata whose hash is embedded in the contract type's bytecode, which must
+// match that hash and the solc version embedded alongside it, checks the
+// keccak256 hash of every source against this package and its installed build
+// dependencies, and returns the metadata along with the standard json input
+// which reproduces the original compilation.
+func (p *PackageManifest) MetadataInput(contractname string,
+	projectdir string,
+	gateway *solcutils.MetadataGateway,
+) (cm *solcutils.CompilerMetadata, stdinjson string, err error) {
+	ct, ok := p.ContractTypes[contractname]
+	if !ok {
+		err = fmt.Errorf("No contract type '%v' in package", contractname)
+		return
+	}
+	var code string
+	if ct.RuntimeBytecode != nil {
+		code = ct.RuntimeBytecode.Bytecode
+	} else if ct.DeploymentBytecode != nil {
+		code = ct.DeploymentBytecode.Bytecode
+	}
+	md, err := bc.ParseMetadata(code)
+	if err != nil {
+		err = fmt.Errorf("Could not read metadata of '%v': '%v'", contractname, err)
+		return
+	}
+	if gateway == nil {
+		gateway = solcutils.NewMetadataGateway("", "")
+	}
+	if cm, err = gateway.Fetch(md); err != nil {
+		return
+	}
+	if projectdir == "" {
+		if projectdir, err = os.Getwd(); err != nil {
+			err = fmt.Errorf("Could not get working directory: '%v'", err)
+			return
+		}
+	}
+	sources, err := p.projectSources(projectdir)
+	if err != nil {
+		return
+	}
+	si, err := cm.StandardInput(sources)
+	if err != nil {
+		err = fmt.Errorf("Sources of '%v' do not match its metadata: '%v'", contractname, err)
+		return
+	}
+	b, _ := json.Marshal(si)
+	stdinjson = string(b)
+	return
+}
@@ -0,0 +1,73 @@
+package ethpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+func TestMetadataInput(t *testing.T) {
+	source := "pragma solidity ^0.5.0;\ncontract A {}\n"
+	hw := sha3.NewKeccak256()
+	hw.Write([]byte(source))
+	metadata := fmt.Sprintf(`{"compiler":{"version":"0.5.17+commit.d19bba13"},"language":"Solidity",`+
+		`"settings":{"optimizer":{"enabled":false,"runs":200}},"sources":{"contracts/A.sol":{"keccak256":"0x%x"}},"version":1}`,
+		hw.Sum(nil))
+	multihash, err := solcutils.IPFSMultihash([]byte(metadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cid, err := solcutils.IPFSCID(multihash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/"+cid {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, metadata)
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "metadata-input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &PackageManifest{PackageName: "a", Sources: map[string]string{"./contracts/A.sol": source}}
+	p.ContractTypes = map[string]*ethcontract.ContractType{
+		"A": {RuntimeBytecode: &bc.UnlinkedBytecode{
+			Bytecode: "0x6080604052a264697066735822" + multihash + "64736f6c63430005110033",
+		}},
+	}
+	gateway := solcutils.NewMetadataGateway(server.URL, "")
+	cm, stdinjson, err := p.MetadataInput("A", dir, gateway)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Compiler.Version != "0.5.17+commit.d19bba13" {
+		t.Fatalf("Got '%v', expected '%v'", cm.Compiler.Version, "0.5.17+commit.d19bba13")
+	}
+	si := &solcutils.StandardInput{}
+	if err = json.Unmarshal([]byte(stdinjson), si); err != nil {
+		t.Fatal(err)
+	}
+	if si.Sources["contracts/A.sol"]["content"] != source {
+		t.Fatalf("Got '%v', expected '%v'", si.Sources["contracts/A.sol"]["content"], source)
+	}
+
+	p.Sources["./contracts/A.sol"] = source + "contract B {}\n"
+	if _, _, err = p.MetadataInput("A", dir, gateway); err == nil {
+		t.Fatalf("Got '%v', expected the changed source to be rejected", err)
+	}
+}
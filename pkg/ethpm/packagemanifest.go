@@ -31,15 +31,19 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/node"
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/compilerutils"
 	"github.com/ethpm/ethpm-go/pkg/ethcontract"
 	"github.com/ethpm/ethpm-go/pkg/ethregexlib"
 	"github.com/ethpm/ethpm-go/pkg/gethutils"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
 	"github.com/ethpm/ethpm-go/pkg/packageregistry"
 	"github.com/ethpm/ethpm-go/pkg/solcutils"
 )
@@ -53,6 +57,7 @@ type PackageManifest struct {
 	Meta              *PackageMeta                                        `json:"meta,omitempty"`
 	PackageName       string                                              `json:"package_name"`
 	Sources           map[string]string                                   `json:"sources,omitempty"`
+	SourceChecksums   map[string]*Checksum                                `json:"-"`
 	Version           string                                              `json:"version"`
 }
 
@@ -67,38 +72,67 @@ func (p *PackageManifest) AddDependency(name string, uri string) {
 }
 
 // AddContractType takes the name of the compiler installed on your system and being used,
-// the settings object from the standard JSON input, https://solidity.readthedocs.io/en/v0.4.24/using-the-compiler.html#input-description,
+// such as "solc" or "vyper", the settings object from the standard JSON input, https://solidity.readthedocs.io/en/v0.4.24/using-the-compiler.html#input-description,
 // the standard JSON output, and the contract name. It then adds the contract type
 // to this manifest.
 func (p *PackageManifest) AddContractType(compiler string, settingsjsonstring string, compileroutputjson string, contractname string) (err error) {
-	var i map[string]map[string]map[string]interface{}
-
 	if len(p.ContractTypes) == 0 {
 		p.ContractTypes = make(map[string]*ethcontract.ContractType)
 	}
 
-	jsonBytes := []byte(compileroutputjson)
-	if err = json.Unmarshal(jsonBytes, &i); err != nil {
+	contractjson, err := contractOutput(compileroutputjson, contractname, "")
+	if err != nil {
 		err = fmt.Errorf("Error getting contract type from JSON for '%v': '%v'", contractname, err)
 		return
 	}
-	for _, v := range i["contracts"] {
-		if v[contractname] != nil {
-			contractbytes, _ := json.Marshal(v[contractname])
-			p.ContractTypes[contractname] = &ethcontract.ContractType{}
-			p.ContractTypes[contractname].Build(compiler, settingsjsonstring, string(contractbytes))
+	if contractjson == "" {
+		return
+	}
+	p.ContractTypes[contractname] = &ethcontract.ContractType{}
+	err = p.ContractTypes[contractname].Build(compiler, settingsjsonstring, contractjson)
+	return
+}
+
+// contractOutput takes the full compiler standard json output and returns the
+// output object of the named contract as a string, or an empty string if no
+// source contains a contract of that name. The sourcepath, if not empty, is the
+// only source searched; otherwise a contract name defined in more than one
+// source is an error.
+func contractOutput(compileroutputjson string, contractname string, sourcepath string) (contractjson string, err error) {
+	var i map[string]map[string]map[string]interface{}
+	if err = json.Unmarshal([]byte(compileroutputjson), &i); err != nil {
+		return
+	}
+	var found []string
+	for k, v := range i["contracts"] {
+		if (v[contractname] != nil) && ((sourcepath == "") || (k == sourcepath)) {
+			found = append(found, k)
 		}
 	}
+	if len(found) == 0 {
+		return
+	}
+	if len(found) > 1 {
+		sort.Strings(found)
+		err = fmt.Errorf("Contract '%v' is defined in more than one source: '%v'", contractname, strings.Join(found, "', '"))
+		return
+	}
+	contractbytes, _ := json.Marshal(i["contracts"][found[0]][contractname])
+	contractjson = string(contractbytes)
 	return
 }
 
 // AddDeployment takes a blockchain uri for a deployed contract instance, a
 // DeployedContractInfo object, and creates a new deployment object for this
-// package. This function is not currently implemented in any workflow.
+// package. It is used by DeployWithLibraries when recording new instances.
 func (p *PackageManifest) AddDeployment(blockchainuri string, d *ethcontract.DeployedContractInfo) {
 	if len(p.Deployments) == 0 {
 		p.Deployments = make(map[string]map[string]*ethcontract.ContractInstance)
 	}
+	if len(p.Deployments[blockchainuri]) == 0 {
+		p.Deployments[blockchainuri] = make(map[string]*ethcontract.ContractInstance)
+	}
+	p.Deployments[blockchainuri][d.ContractName] = &ethcontract.ContractInstance{}
 	p.Deployments[blockchainuri][d.ContractName].Build(d)
 	return
 }
@@ -107,43 +141,25 @@ func (p *PackageManifest) AddDeployment(blockchainuri string, d *ethcontract.Dep
 // such as "sol", then adds this source to the package manifest. The source relative
 // path should contain the path relative to the manifest json location. It can
 // be an empty string, in which case, the path will be the same directory.
+// Subdirectories are included, and files ignored by .gitignore are skipped. The
+// keccak256 checksum of every source is recorded in SourceChecksums.
 func (p *PackageManifest) SourceInliner(contractdir string, sourcerelativepath string, sourcetype string) (err error) {
-	if contractdir == "" {
-		if contractdir, err = os.Getwd(); err != nil {
-			err = fmt.Errorf("Could not get working directory: '%v'", err)
-			return
-		}
-	}
-	if sourcerelativepath == "" {
-		sourcerelativepath = "./"
-	}
-	if len(p.Sources) == 0 {
-		p.Sources = make(map[string]string)
-	}
-	files, err := ioutil.ReadDir(contractdir)
-	if err != nil {
-		err = fmt.Errorf("Could not get read directory: '%v'", err)
-		return
-	}
-	for _, f := range files {
-		n := f.Name()
-		if n[len(n)-3:] == sourcetype {
-			if b, thiserr := ioutil.ReadFile(filepath.Join(contractdir, n)); thiserr != nil {
-				err = fmt.Errorf("Could not get read %v: '%v'", n, thiserr)
-			} else {
-				p.Sources[sourcerelativepath+n] = string(b)
-			}
-		}
-	}
-	return
+	return p.addSourceDir(contractdir, sourcerelativepath, sourcetype, true)
 }
 
 // AddLocalPathForSource takes the contract directory, which can be left empty and
 // the current working directory will be used, the source path relative to the location
 // of the manifest file, which can be empty and will be the same directory, and the
 // the source type, generally .sol. It will then add a source file location as relative
-// to the manifest.
+// to the manifest and record the keccak256 checksum of its content. Subdirectories
+// are included, and files ignored by .gitignore are skipped.
 func (p *PackageManifest) AddLocalPathForSource(contractdir string, sourcerelativepath string, sourcetype string) (err error) {
+	return p.addSourceDir(contractdir, sourcerelativepath, sourcetype, false)
+}
+
+// addSourceDir collects the sources of the given type in the contract directory
+// and adds them under the source relative path
+func (p *PackageManifest) addSourceDir(contractdir string, sourcerelativepath string, sourcetype string, inline bool) (err error) {
 	if contractdir == "" {
 		if contractdir, err = os.Getwd(); err != nil {
 			err = fmt.Errorf("Could not get working directory: '%v'", err)
@@ -153,31 +169,26 @@ func (p *PackageManifest) AddLocalPathForSource(contractdir string, sourcerelati
 	if sourcerelativepath == "" {
 		sourcerelativepath = "./"
 	}
-	if len(p.Sources) == 0 {
-		p.Sources = make(map[string]string)
-	}
-	files, err := ioutil.ReadDir(contractdir)
+	files, err := NewSourceCollector(sourcetype).Collect(contractdir)
 	if err != nil {
-		err = fmt.Errorf("Could not get read directory: '%v'", err)
 		return
 	}
-	for _, f := range files {
-		n := f.Name()
-		if n[len(n)-3:] == sourcetype {
-			p.Sources[sourcerelativepath+n] = sourcerelativepath + n
-		}
-	}
-	return
+	return p.addSourceFiles(contractdir, files, sourcerelativepath, inline)
 }
 
 // CompileAndValidateSource takes the name of the installed compiler, such as
-// solc, the project directory, contract name, if the source is inlined in the
+// solc or vyper, the project directory, contract name, if the source is inlined in the
 // package manifest, set inline to true, the source file path, if source is inline
 // this should be equal to the key identifying the source, the full file path to source
 // if not inline, compiler optimize setting (true or false), and the number of runs
-// for the optimizer (will be ignored if optimize is fale). It will then compile
-// the provided contract and compare to the equivalent contract type in the manifest.
-// If it is a match, then valid will return true, if not, it should return false.
+// for the optimizer (will be ignored if optimize is fale), and a solc manager,
+// which can be nil to always use the installed compiler. If a manager is given
+// the solc version recorded in the contract type is installed and used. Vyper
+// sources are recognized by their ".vy" extension. It will then compile the
+// provided contract and compare to the equivalent contract type in the manifest.
+// If it is a match, then valid will return true and match the way the bytecode
+// matched, either exactly, by the metadata hash or ignoring the metadata, if
+// not, it should return false.
 // producedobject is the string representation of the generated contract type.
 //
 // This has not been incorporated into any workflow nor rigorously tested.
@@ -188,28 +199,38 @@ func (p *PackageManifest) CompileAndValidateSource(compiler string,
 	filepath string,
 	optimize bool,
 	runs int,
-) (valid bool, producedobject string, err error) {
-	var fileasstring string
-	if inline {
-		if len(p.Sources[filepath]) == 0 {
-			err = fmt.Errorf("Invalid inline source key: '%v'", err)
+	manager *solcutils.SolcManager,
+) (valid bool, match bc.MatchMode, producedobject string, err error) {
+	ct, ok := p.ContractTypes[contractname]
+	if !ok {
+		err = fmt.Errorf("No contract type '%v' in package", contractname)
+		return
+	}
+	if ct.Compiler != nil {
+		if compiler, err = manager.Resolve(compiler, ct.Compiler.Version); err != nil {
+			err = fmt.Errorf("Error getting compiler version '%v': '%v'", ct.Compiler.Version, err)
 			return
 		}
-		fileasstring = p.Sources[filepath]
-	}
-	dependencies := make([]string, 0)
-	for k := range p.BuildDependencies {
-		dependencies = append(dependencies, k)
-	}
-	stdinjson, stdoutjson, err := solcutils.CompileFileAsString(compiler,
-		projectdir,
-		contractname,
-		inline,
-		filepath,
-		fileasstring,
-		dependencies,
-		optimize,
-		runs)
+	}
+	c, err := compilerutils.NewCompiler(compiler, projectdir)
+	if err != nil {
+		return
+	}
+	if language := compilerutils.SourceLanguage(filepath); language != c.Language() {
+		err = fmt.Errorf("Source '%v' is %v and cannot be compiled with '%v'", filepath, language, c.Name())
+		return
+	}
+	source, err := p.compilerSource(c, inline, filepath)
+	if err != nil {
+		return
+	}
+	settings := solcutils.NewSolcSettings(optimize, runs)
+	settings.Remappings = solcutils.DependencyRemappings(p.dependencyNames())
+	stdinjson, err := compilerutils.BuildInput(c, map[string]map[string]string{contractname: source}, settings)
+	if err != nil {
+		return
+	}
+	stdoutjson, err := c.CompileStandardJSON(stdinjson)
 	if err != nil {
 		err = fmt.Errorf("Error compiling source: '%v'", err)
 		return
@@ -222,17 +243,53 @@ func (p *PackageManifest) CompileAndValidateSource(compiler string,
 		return
 	}
 	settingsbytes, _ := json.Marshal(s["settings"])
+	contractjson, err := contractOutput(stdoutjson, contractname, "")
+	if err != nil {
+		err = fmt.Errorf("Error getting contract '%v' from compiler output: '%v'", contractname, err)
+		return
+	}
+	if contractjson == "" {
+		err = fmt.Errorf("Contract '%v' not found in compiler output", contractname)
+		return
+	}
 	ec := &ethcontract.ContractType{}
-	err = ec.Build(compiler, string(settingsbytes), stdoutjson)
+	err = ec.Build(compiler, string(settingsbytes), contractjson)
 	if err != nil {
 		err = fmt.Errorf("Error building the contracty type object: '%v'", err)
 		return
 	}
+	ec.Compiler.SetName(c.Name())
 	b, _ := json.Marshal(ec)
 	producedobject = string(b)
-	if ec.DeploymentBytecode.Bytecode == p.ContractTypes[contractname].DeploymentBytecode.Bytecode {
-		valid = true
+	if ct.DeploymentBytecode != nil {
+		match = bc.Compare(ec.DeploymentBytecode.Bytecode, ct.DeploymentBytecode.Bytecode)
+		valid = (match != "")
+	}
+	return
+}
+
+// compilerSource returns the standard json source object for a source. Solc
+// reads files from the project itself, vyper is always given the content.
+func (p *PackageManifest) compilerSource(c compilerutils.Compiler, inline bool, sourcepath string) (source map[string]string, err error) {
+	source = make(map[string]string)
+	if inline {
+		if len(p.Sources[sourcepath]) == 0 {
+			err = fmt.Errorf("Invalid inline source key: '%v'", sourcepath)
+			return
+		}
+		source["content"] = p.Sources[sourcepath]
+		return
+	}
+	if c.Language() == compilerutils.Solidity {
+		source["url"] = sourcepath
+		return
 	}
+	b, err := ioutil.ReadFile(sourcepath)
+	if err != nil {
+		err = fmt.Errorf("Could not read source '%v': '%v'", sourcepath, err)
+		return
+	}
+	source["content"] = string(b)
 	return
 }
 
@@ -307,7 +364,15 @@ func (p *PackageManifest) PublishToRepositoryWithPassword(repositoryaddressashex
 
 // Validate ensures PackageManifest conforms to the standard defined here
 // https://ethpm.github.io/ethpm-spec/package-spec.html#document-specification
+// Local source paths and installed dependencies are looked up relative to the
+// current working directory.
 func (p *PackageManifest) Validate() (err error) {
+	return p.validate("")
+}
+
+// validate is Validate with local source paths and installed dependencies looked
+// up relative to the manifest directory, the working directory if empty
+func (p *PackageManifest) validate(manifestdir string) (err error) {
 	if retErr := checkManifestVersion(p.ManifestVersion); retErr != nil {
 		err = fmt.Errorf("PackageManifest:manifest_version returned error '%v'", retErr)
 		return
@@ -326,7 +391,7 @@ func (p *PackageManifest) Validate() (err error) {
 			return
 		}
 	}
-	if retErr := checkSources(p.Sources); retErr != nil {
+	if retErr := checkSources(p.Sources, manifestdir); retErr != nil {
 		err = fmt.Errorf("PackageManifest:sources returned error '%v'", retErr)
 		return
 	}
@@ -334,7 +399,15 @@ func (p *PackageManifest) Validate() (err error) {
 		err = fmt.Errorf("PackageManifest:contract_types returned error '%v'", retErr)
 		return
 	}
-	if retErr := checkDeployments(p.Deployments); retErr != nil {
+	if retErr := checkLinkReferenceNames(p.ContractTypes); retErr != nil {
+		err = fmt.Errorf("PackageManifest:contract_types returned error '%v'", retErr)
+		return
+	}
+	tree := NewDependencyTree(p)
+	if manifestdir != "" {
+		tree = NewDependencyTree(p, manifestdir)
+	}
+	if retErr := checkDeployments(p.Deployments, tree); retErr != nil {
 		err = fmt.Errorf("PackageManifest:deployments returned error '%v'", retErr)
 		return
 	}
@@ -355,33 +428,26 @@ func checkManifestVersion(s string) error {
 }
 
 // checkSources ensures the keys and values in the source mapping is formatted correctly
-func checkSources(s map[string]string) (err error) {
-	var uri *url.URL
-
+func checkSources(s map[string]string, manifestdir string) (err error) {
+	re := regexp.MustCompile("^(?:[\\w]\\:|\\.\\/)([a-zA-Z_\\-\\s0-9\\.]+(?:\\\\|\\/)?)+$")
 	for k, v := range s {
-		uri, err = url.Parse(v)
-		if err != nil {
-			break
-		} else {
-			if a := uri.IsAbs(); !a {
-				_, err = os.Stat(v)
-				if err != nil {
-					err = fmt.Errorf("Source with key '%v' and location value '%v' does not exist or is unreachable. "+
-						"Please check the url or filepath and fix or consider contacting the maintainer.", k, v)
-					break
-				}
+		uri, retErr := url.Parse(v)
+		if (retErr == nil) && isSourceLocation(v) && !uri.IsAbs() {
+			location := filepath.FromSlash(v)
+			if !filepath.IsAbs(location) {
+				location = filepath.Join(manifestdir, location)
 			}
-		}
-		if err != nil {
-			err = nil
-		} else {
-			re := regexp.MustCompile("^(?:[\\w]\\:|\\.\\/)([a-z_\\-\\s0-9\\.]+(?:\\\\|\\/)?)+$")
-			matched := re.MatchString(k)
-			if !matched {
-				err = fmt.Errorf("Invalid path for source key '%v'. Please make this a relative path in accordance "+
-					"with the spec found here https://ethpm.github.io/ethpm-spec/package-spec.html#sources-sources.", k)
+			if _, retErr = os.Stat(location); retErr != nil {
+				err = fmt.Errorf("Source with key '%v' and location value '%v' does not exist or is unreachable. "+
+					"Please check the url or filepath and fix or consider contacting the maintainer.", k, v)
+				break
 			}
 		}
+		if matched := re.MatchString(k); !matched {
+			err = fmt.Errorf("Invalid path for source key '%v'. Please make this a relative path in accordance "+
+				"with the spec found here https://ethpm.github.io/ethpm-spec/package-spec.html#sources-sources.", k)
+			break
+		}
 	}
 	return
 }
@@ -403,7 +469,22 @@ func checkContractTypes(ct map[string]*ethcontract.ContractType) (err error) {
 	return
 }
 
-func checkDeployments(d map[string]map[string]*ethcontract.ContractInstance) (err error) {
+// checkLinkReferenceNames ensures libraries linked by the contract types, which
+// may come from different dependencies, can be told apart by name
+func checkLinkReferenceNames(ct map[string]*ethcontract.ContractType) error {
+	var refs []*liblink.LinkReference
+	for _, v := range ct {
+		if v.DeploymentBytecode != nil {
+			refs = append(refs, v.DeploymentBytecode.LinkReferences...)
+		}
+		if v.RuntimeBytecode != nil {
+			refs = append(refs, v.RuntimeBytecode.LinkReferences...)
+		}
+	}
+	return liblink.CheckAmbiguousNames(refs)
+}
+
+func checkDeployments(d map[string]map[string]*ethcontract.ContractInstance, tree *DependencyTree) (err error) {
 OuterLoop:
 	for k, v := range d {
 		if retErr := ethregexlib.CheckBIP122URI(k); retErr != nil {
@@ -418,9 +499,13 @@ OuterLoop:
 				break OuterLoop
 			}
 			dependencyLengths := make(map[string]int)
-			for _, y := range z.RuntimeBytecode.LinkDependencies {
+			var linkDependencies []*liblink.LinkValue
+			if z.RuntimeBytecode != nil {
+				linkDependencies = z.RuntimeBytecode.LinkDependencies
+			}
+			for _, y := range linkDependencies {
 				if y.Type == "reference" {
-					dependencyLengths[y.Value], err = getLinkValueDependencyLength(k, v, y.Value)
+					dependencyLengths[y.Value], err = getLinkValueDependencyLength(k, tree, y.Value)
 					if err != nil {
 						err = fmt.Errorf("deployment[%v]:contract_instance[%v] returned the following dependency "+
 							"error for dependency '%v'. Please ensure you have the dependency installed correctly: "+
@@ -439,49 +524,18 @@ OuterLoop:
 	return
 }
 
-func getLinkValueDependencyLength(blockchainURI string, thisDeps map[string]*ethcontract.ContractInstance, d string) (length int, err error) {
+// getLinkValueDependencyLength returns the byte length of the address of the
+// contract instance a link value references through the dependency tree
+func getLinkValueDependencyLength(blockchainURI string, tree *DependencyTree, d string) (length int, err error) {
 	if d == "" {
 		return
 	}
-
-	var currentDir string
-
-	if retErr := ethregexlib.CheckDependencyTree(d); retErr != nil {
-		err = retErr
-		return
-	}
-	if currentDir, err = os.Getwd(); err != nil {
+	ci, _, err := tree.ResolveInstance(blockchainURI, d)
+	if err != nil {
 		return
 	}
-	depTree := strings.Split(d, ":")
-	treeLength := len(depTree)
-	var depPath strings.Builder
-	depPath.WriteString(currentDir)
-	if treeLength > 1 {
-		for i := 0; i < (treeLength - 2); i++ {
-			depPath.WriteString("/ethpm-dependencies/" + depTree[i])
-		}
-		depPath.WriteString("/" + depTree[treeLength-2] + ".json")
-
-		var file *os.File
-		if file, err = os.Open(filepath.FromSlash(depPath.String())); err != nil {
-			return
-		}
-		manifest := make([]byte, 100)
-		_, err = file.Read(manifest)
-		if err != nil {
-			return
-		}
-		pm := PackageManifest{}
-		err = json.Unmarshal(manifest, &pm)
-		if err != nil {
-			return
-		}
-		bc := pm.Deployments[blockchainURI][depTree[treeLength-1]].Address
-		return ((len(bc) - 2) / 2), nil
-	}
-	bc := thisDeps[depTree[0]].Address
-	return ((len(bc) - 2) / 2), nil
+	length = (len(ci.Address) - 2) / 2
+	return
 }
 
 func checkBuildDependencies(bd map[string]string) (err error) {
@@ -501,11 +555,10 @@ func checkBuildDependencies(bd map[string]string) (err error) {
 				}
 			}
 		}
-		re := regexp.MustCompile("^(?:[\\w]\\:|\\.\\/)([a-z_\\-\\s0-9\\.]+(?:\\\\|\\/)?)+$")
-		matched := re.MatchString(k)
-		if !matched {
-			err = fmt.Errorf("Invalid path for source key '%v'. Please make this a relative path in accordance "+
-				"with the spec found here https://ethpm.github.io/ethpm-spec/package-spec.html#build-dependencies-build-dependencies.", k)
+		if retErr := ethregexlib.CheckPackageName(k); retErr != nil {
+			err = fmt.Errorf("Invalid package name for build dependency key '%v': '%v'. Please see the spec found here "+
+				"https://ethpm.github.io/ethpm-spec/package-spec.html#build-dependencies-build-dependencies.", k, retErr)
+			break
 		}
 	}
 	return
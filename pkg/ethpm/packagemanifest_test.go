@@ -6,6 +6,10 @@ import (
 	"io/ioutil"
 	"log"
 	"testing"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	liblink "github.com/ethpm/ethpm-go/pkg/librarylink"
 )
 
 func TestAddDependency(t *testing.T) {
@@ -373,6 +377,9 @@ func TestSourceInliner(t *testing.T) {
 	if got != string(want) {
 		t.Fatalf("Got '%v', expected '%v'", got, want)
 	}
+	if err := p.SourceChecksums["./BasicMathLib.sol"].Verify(want); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func ExamplePackageManifest_SourceInliner() {
@@ -401,6 +408,9 @@ func TestAddLocalPathForSource(t *testing.T) {
 	if got != string(want) {
 		t.Fatalf("Got '%v', expected '%v'", got, want)
 	}
+	if c := p.SourceChecksums["./contracts/BasicMathLib.sol"]; (c == nil) || (c.Algorithm != ChecksumKeccak256) {
+		t.Fatalf("Got '%v', expected a keccak256 checksum", c)
+	}
 }
 
 func ExamplePackageManifest_AddLocalPathForSource() {
@@ -455,6 +465,27 @@ func TestManifestValidate(t *testing.T) {
 	}
 }
 
+func TestCheckLinkReferenceNames(t *testing.T) {
+	ct := map[string]*ethcontract.ContractType{
+		"Token": {DeploymentBytecode: &bc.UnlinkedBytecode{
+			LinkReferences: []*liblink.LinkReference{{Name: "SafeMath", Source: "contracts/Math.sol"}},
+		}},
+		"Wallet": {RuntimeBytecode: &bc.UnlinkedBytecode{
+			LinkReferences: []*liblink.LinkReference{{Name: "SafeMath", Source: "contracts/Math.sol"}},
+		}},
+	}
+	if got := checkLinkReferenceNames(ct); got != nil {
+		t.Fatalf("Got '%v', expected '<nil>'", got)
+	}
+
+	ct["Wallet"].RuntimeBytecode.LinkReferences[0].Source = "./node_modules/wallet/Math.sol"
+	want := errors.New("Library name 'SafeMath' is ambiguous, it is linked as " +
+		"'./node_modules/wallet/Math.sol:SafeMath', 'contracts/Math.sol:SafeMath'")
+	if got := checkLinkReferenceNames(ct); (got == nil) || (got.Error() != want.Error()) {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
 func TestCheckSources(t *testing.T) {
 	var want error
 	var got error
@@ -462,7 +493,7 @@ func TestCheckSources(t *testing.T) {
 
 	p.Sources = make(map[string]string)
 	p.Sources["./hello"] = "/Nick"
-	got = checkSources(p.Sources)
+	got = checkSources(p.Sources, "")
 	want = errors.New("Source with key './hello' and location value '/Nick' does not exist or is unreachable. " +
 		"Please check the url or filepath and fix or consider contacting the maintainer.")
 	if got.Error() != want.Error() {
@@ -471,7 +502,7 @@ func TestCheckSources(t *testing.T) {
 
 	delete(p.Sources, "./hello")
 	p.Sources["/not-valid-other-chris"] = "https://github.com/modular-network"
-	got = checkSources(p.Sources)
+	got = checkSources(p.Sources, "")
 	want = errors.New("Invalid path for source key '/not-valid-other-chris'. Please make this a relative path in accordance " +
 		"with the spec found here https://ethpm.github.io/ethpm-spec/package-spec.html#sources-sources.")
 	if got.Error() != want.Error() {
@@ -480,8 +511,26 @@ func TestCheckSources(t *testing.T) {
 
 	delete(p.Sources, "/not-valid-other-chris")
 	p.Sources["./contracts"] = "https://github.com/modular-network/ethereum-libraries"
-	got = checkSources(p.Sources)
+	got = checkSources(p.Sources, "")
+	if got != nil {
+		t.Fatalf("Got '%v', expected '<nil>'", got)
+	}
+
+	p.Sources["./contracts/Token.sol"] = "pragma solidity ^0.4.24;\n\ncontract Token {}\n"
+	p.Sources["./BasicMathLib.sol"] = "./BasicMathLib.sol"
+	got = checkSources(p.Sources, "../../test/testdata")
 	if got != nil {
 		t.Fatalf("Got '%v', expected '<nil>'", got)
 	}
 }
+
+func TestCheckBuildDependencies(t *testing.T) {
+	bd := map[string]string{"owned": "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW"}
+	if got := checkBuildDependencies(bd); got != nil {
+		t.Fatalf("Got '%v', expected '<nil>'", got)
+	}
+	bd["./owned"] = "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW"
+	if got := checkBuildDependencies(bd); got == nil {
+		t.Fatalf("Got '%v', expected an error for an invalid package name", got)
+	}
+}
@@ -0,0 +1,257 @@
+package ethpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ProjectFile is the name of the project config describing how to build a package
+const ProjectFile = "ethpm.yaml"
+
+// ProjectConfig is the declarative description of a package read from an
+// ethpm.yaml project file. Build turns it into a validated PackageManifest.
+//
+//	package_name: token
+//	version: 1.0.0
+//	meta:
+//	  authors: [Alice]
+//	  license: MIT
+//	sources:
+//	  include: ["contracts/**"]
+//	  inline: true
+//	compiler:
+//	  name: solc
+//	  version: 0.4.24
+//	  settings:
+//	    optimizer: {enabled: true, runs: 200}
+//	contract_types: [Token]
+//	dependencies:
+//	  owned: {uri: "ipfs://Qm...", version: "^1.0.0"}
+//	deployments:
+//	  "blockchain://d4e5.../block/1e96...":
+//	    Token: {address: "0x...", contract_type: Token}
+type ProjectConfig struct {
+	Compiler      *CompilerConfig                         `yaml:"compiler,omitempty"`
+	ContractTypes []string                                `yaml:"contract_types,omitempty"`
+	Dependencies  map[string]*DependencyConfig            `yaml:"dependencies,omitempty"`
+	Deployments   map[string]map[string]*DeploymentConfig `yaml:"deployments,omitempty"`
+	Meta          *PackageMeta                            `yaml:"meta,omitempty"`
+	PackageName   string                                  `yaml:"package_name"`
+	Sources       *SourcesConfig                          `yaml:"sources,omitempty"`
+	Version       string                                  `yaml:"version"`
+}
+
+// CompilerConfig names the compiler, the solc version to install if a solc
+// manager is used, and the settings object of the standard json input
+type CompilerConfig struct {
+	Name     string                 `yaml:"name,omitempty"`
+	Settings map[string]interface{} `yaml:"settings,omitempty"`
+	Version  string                 `yaml:"version,omitempty"`
+}
+
+// DependencyConfig is a build dependency given by its uri, along with the range
+// of versions of it the package works with, such as "^1.0.0"
+type DependencyConfig struct {
+	URI     string `yaml:"uri"`
+	Version string `yaml:"version,omitempty"`
+}
+
+// DeploymentConfig is a contract instance of the package deployed on a chain
+type DeploymentConfig struct {
+	Address      string `yaml:"address"`
+	Block        string `yaml:"block,omitempty"`
+	ContractType string `yaml:"contract_type"`
+	Transaction  string `yaml:"transaction,omitempty"`
+}
+
+// SourcesConfig selects the sources of the package, see SourceCollector. Sources
+// are inlined in the manifest if Inline is set.
+type SourcesConfig struct {
+	Exclude    []string `yaml:"exclude,omitempty"`
+	Extensions []string `yaml:"extensions,omitempty"`
+	Include    []string `yaml:"include,omitempty"`
+	Inline     bool     `yaml:"inline,omitempty"`
+}
+
+// ReadProjectConfig reads and parses the project file at the given path
+func ReadProjectConfig(path string) (pc *ProjectConfig, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("Could not read project file: '%v'", err)
+		return
+	}
+	return ParseProjectConfig(string(b))
+}
+
+// ParseProjectConfig takes the yaml content of a project file and returns the
+// ProjectConfig struct
+func ParseProjectConfig(yamlstring string) (pc *ProjectConfig, err error) {
+	pc = &ProjectConfig{}
+	if err = yaml.UnmarshalStrict([]byte(yamlstring), pc); err != nil {
+		err = fmt.Errorf("Error parsing project file: '%v'", err)
+	}
+	return
+}
+
+// Build takes the project directory, which can be empty for the current working
+// directory, and a solc manager, which can be nil to always use the installed
+// compiler. It collects the sources, checks the versions of the installed
+// dependencies against their ranges, compiles the project if contract types are
+// exported, adds the deployments and returns the validated manifest.
+func (pc *ProjectConfig) Build(projectdir string, manager *solcutils.SolcManager) (p *PackageManifest, err error) {
+	if projectdir == "" {
+		if projectdir, err = os.Getwd(); err != nil {
+			err = fmt.Errorf("Could not get working directory: '%v'", err)
+			return
+		}
+	}
+	if p, err = CreateNewManifest(pc.PackageName, pc.Version); err != nil {
+		return
+	}
+	p.Meta = pc.Meta
+	if err = pc.addDependencies(p, projectdir); err != nil {
+		return
+	}
+	sc := NewSourceCollector()
+	inline := false
+	if s := pc.Sources; s != nil {
+		if len(s.Extensions) > 0 {
+			sc.Extensions = s.Extensions
+		}
+		sc.Exclude = append(sc.Exclude, s.Exclude...)
+		sc.Include = s.Include
+		inline = s.Inline
+	}
+	if err = p.AddSources(projectdir, sc, inline); err != nil {
+		return
+	}
+	if err = pc.addContractTypes(p, projectdir, manager); err != nil {
+		return
+	}
+	for chain, instances := range pc.Deployments {
+		if len(p.Deployments) == 0 {
+			p.Deployments = make(map[string]map[string]*ethcontract.ContractInstance)
+		}
+		p.Deployments[chain] = make(map[string]*ethcontract.ContractInstance)
+		for name, d := range instances {
+			p.Deployments[chain][name] = &ethcontract.ContractInstance{
+				Address:      d.Address,
+				Block:        d.Block,
+				ContractType: d.ContractType,
+				Transaction:  d.Transaction,
+			}
+		}
+	}
+	if err = p.validate(projectdir); err != nil {
+		err = fmt.Errorf("Built manifest is not valid: '%v'", err)
+	}
+	return
+}
+
+// addDependencies adds the build dependencies, checking the version of every
+// dependency with a range against the installed manifest
+func (pc *ProjectConfig) addDependencies(p *PackageManifest, projectdir string) (err error) {
+	names := make([]string, 0, len(pc.Dependencies))
+	for k := range pc.Dependencies {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := pc.Dependencies[name]
+		p.AddDependency(name, d.URI)
+		if d.Version == "" {
+			continue
+		}
+		dep, retErr := readInstalledManifest(projectdir, name)
+		if retErr != nil {
+			err = fmt.Errorf("Could not check the version of dependency '%v', is it installed? '%v'", name, retErr)
+			return
+		}
+		ok, retErr := MatchVersionRange(dep.Version, d.Version)
+		if retErr != nil {
+			err = fmt.Errorf("Dependency '%v': %v", name, retErr)
+			return
+		}
+		if !ok {
+			err = fmt.Errorf("Installed dependency '%v' is version '%v', expected '%v'", name, dep.Version, d.Version)
+			return
+		}
+	}
+	return
+}
+
+// addContractTypes compiles the project and adds the exported contract types
+func (pc *ProjectConfig) addContractTypes(p *PackageManifest, projectdir string, manager *solcutils.SolcManager) (err error) {
+	if len(pc.ContractTypes) == 0 {
+		return
+	}
+	compiler, version := "solc", ""
+	settings := solcutils.NewSolcSettings(false, 0)
+	if c := pc.Compiler; c != nil {
+		if c.Name != "" {
+			compiler = c.Name
+		}
+		version = c.Version
+		if len(c.Settings) > 0 {
+			b, retErr := json.Marshal(jsonValue(c.Settings))
+			if retErr != nil {
+				err = fmt.Errorf("Invalid compiler settings: '%v'", retErr)
+				return
+			}
+			if err = json.Unmarshal(b, settings); err != nil {
+				err = fmt.Errorf("Invalid compiler settings: '%v'", err)
+				return
+			}
+		}
+	}
+	// The resolved path, such as a managed 'solc-0.4.24+commit.e67f0147', is
+	// recorded as 'solc' or 'vyper' by AddContractType
+	if compiler, err = manager.Resolve(compiler, version); err != nil {
+		return
+	}
+	_, stdoutjson, err := p.CompileProject(compiler, projectdir, settings)
+	if err != nil {
+		return
+	}
+	settingsjson, _ := json.Marshal(settings)
+	for _, name := range pc.ContractTypes {
+		if err = p.AddContractType(compiler, string(settingsjson), stdoutjson, name); err != nil {
+			return
+		}
+		if _, ok := p.ContractTypes[name]; !ok {
+			err = fmt.Errorf("No contract '%v' in the compiler output", name)
+			return
+		}
+	}
+	return
+}
+
+// jsonValue converts the maps yaml decodes into maps json can encode
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range t {
+			t[i] = jsonValue(e)
+		}
+	}
+	return v
+}
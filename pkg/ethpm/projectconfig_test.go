@@ -0,0 +1,141 @@
+package ethpm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectConfigBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"contracts/Token.sol":                        "contract Token {}\n",
+		"contracts/mocks/MockToken.sol":              "contract MockToken {}\n",
+		"ethpm-dependencies/owned/ethpm.json":        `{"manifest_version":"2","package_name":"owned","version":"1.2.0"}`,
+		"ethpm-dependencies/owned/contracts/O.sol":   "contract Owned {}\n",
+		"ethpm-dependencies/ignored/contracts/I.sol": "contract Ignored {}\n",
+	}
+	writeTestFiles(t, dir, files)
+	config := `
+package_name: token
+version: 1.0.0
+meta:
+  authors: [Alice]
+  license: MIT
+sources:
+  include: ["contracts/**"]
+  exclude: [mocks]
+  inline: true
+dependencies:
+  owned: {uri: "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW", version: "^1.0.0"}
+deployments:
+  "blockchain://d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3/block/1e96de11320c83cca02e8b9caf3e489497e8e432befe5379f2f08599f8aecede":
+    Token: {address: "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0", contract_type: Token}
+`
+	if err = ioutil.WriteFile(filepath.Join(dir, ProjectFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pc, err := ReadProjectConfig(filepath.Join(dir, ProjectFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := pc.Build(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (len(p.Sources) != 1) || (p.Sources["./contracts/Token.sol"] != "contract Token {}\n") {
+		t.Fatalf("Got '%v', expected only the inlined Token source", p.Sources)
+	}
+	if p.BuildDependencies["owned"] != "ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW" {
+		t.Fatalf("Got '%v', expected the owned dependency", p.BuildDependencies)
+	}
+	if (p.Meta == nil) || (p.Meta.License != "MIT") || (len(p.Meta.Authors) != 1) {
+		t.Fatalf("Got '%+v', expected the meta of the project file", p.Meta)
+	}
+	if len(p.Deployments) != 1 {
+		t.Fatalf("Got '%v' deployment chains, expected '%v'", len(p.Deployments), 1)
+	}
+
+	pc.Dependencies["owned"].Version = "^2.0.0"
+	if _, err = pc.Build(dir, nil); err == nil {
+		t.Fatalf("Got '%v', expected an error for a dependency out of range", err)
+	}
+	if _, err = ParseProjectConfig("package_name: token\nversoin: 1.0.0\n"); err == nil {
+		t.Fatalf("Got '%v', expected an error for an unknown field", err)
+	}
+}
+
+func TestProjectConfigBuildLanguages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeTestFiles(t, dir, map[string]string{
+		"contracts/Token.sol": "contract Token {}\n",
+		"contracts/Vault.vy":  "@public\ndef deposit():\n    pass\n",
+	})
+	bin := filepath.Join(dir, "bin")
+	contract := `{"abi":[],"evm":{"bytecode":{"object":"0x6001"},"deployedBytecode":{"object":"0x6002"}}}`
+	writeTestFiles(t, bin, map[string]string{
+		"solc": "#!/bin/sh\nif [ \"$1\" = \"--version\" ]; then echo 'Version: 0.4.24+commit.e67f0147.Linux.g++'; exit 0; fi\n" +
+			"cat > '" + filepath.Join(bin, "solc.json") + "'\n" +
+			"echo '{\"contracts\":{\"contracts/Token.sol\":{\"Token\":" + contract + "}}}'\n",
+		"vyper": "#!/bin/sh\nif [ \"$1\" = \"--version\" ]; then echo '0.1.0b4'; exit 0; fi\n" +
+			"cat > '" + filepath.Join(bin, "vyper.json") + "'\n" +
+			"echo '{\"contracts\":{\"contracts/Vault.vy\":{\"Vault\":" + contract + "}}}'\n",
+	})
+	for _, name := range []string{"solc", "vyper"} {
+		if err = os.Chmod(filepath.Join(bin, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+	defer os.Setenv("PATH", path)
+
+	tests := []struct {
+		compiler string
+		contract string
+		language string
+		source   string
+	}{
+		{"solc", "Token", "Solidity", "contracts/Token.sol"},
+		{"vyper", "Vault", "Vyper", "contracts/Vault.vy"},
+	}
+	for _, tt := range tests {
+		config := "package_name: token\nversion: 1.0.0\nsources:\n  include: [\"contracts/**\"]\n" +
+			"compiler: {name: " + tt.compiler + "}\ncontract_types: [" + tt.contract + "]\n"
+		pc, err := ParseProjectConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := pc.Build(dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct := p.ContractTypes[tt.contract]; (ct == nil) || (ct.Compiler.Name != tt.compiler) {
+			t.Fatalf("Got '%+v', expected contract type '%v' compiled with '%v'", ct, tt.contract, tt.compiler)
+		}
+		b, err := ioutil.ReadFile(filepath.Join(bin, tt.compiler+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var input struct {
+			Language string                 `json:"language"`
+			Sources  map[string]interface{} `json:"sources"`
+		}
+		if err = json.Unmarshal(b, &input); err != nil {
+			t.Fatal(err)
+		}
+		if (input.Language != tt.language) || (len(input.Sources) != 1) || (input.Sources[tt.source] == nil) {
+			t.Fatalf("Got '%v', expected a %v input of '%v' only", string(b), tt.language, tt.source)
+		}
+	}
+}
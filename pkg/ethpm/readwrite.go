@@ -30,7 +30,8 @@ func (p *PackageManifest) Write() (s string, err error) {
 
 // WriteToDisk takes a PackageManifest struct, validates, and writes it to the
 // location defined by directoryname. If directoryname is an empty string, it
-// writes to the current working directory.
+// writes to the current working directory. Checksums of sources are written to
+// the ethpm.checksums.json sidecar.
 func (p *PackageManifest) WriteToDisk(directoryname string) (err error) {
 	var pm *os.File
 
@@ -51,7 +52,7 @@ func (p *PackageManifest) WriteToDisk(directoryname string) (err error) {
 
 	if pm, err = os.Open(f); os.IsNotExist(err) {
 		err = nil
-		pm, err = os.Create(directoryname + "ethpm.json")
+		pm, err = os.Create(f)
 		if err != nil {
 			err = fmt.Errorf("Could not create file ethpm.json: '%v'", err)
 			return
@@ -68,5 +69,8 @@ func (p *PackageManifest) WriteToDisk(directoryname string) (err error) {
 		return
 	}
 	pm.Sync()
+	if len(p.SourceChecksums) > 0 {
+		err = p.WriteChecksums(directoryname)
+	}
 	return
 }
@@ -0,0 +1,252 @@
+package ethpm
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// SourceCollector finds the source files of a project by walking its directory
+// recursively. Files must have one of the extensions, such as "sol" or "vy",
+// match one of the include patterns if there are any, and match none of the
+// exclude patterns. Patterns are slash separated globs relative to the project
+// directory where "**" matches any number of directories, and patterns without
+// a slash match a file or directory name at any depth. If GitIgnore is set,
+// files ignored by the .gitignore files of the project are skipped as well.
+type SourceCollector struct {
+	Exclude    []string
+	Extensions []string
+	GitIgnore  bool
+	Include    []string
+}
+
+// ignoreRule is a pattern of a .gitignore file, relative to its directory
+type ignoreRule struct {
+	anchored bool
+	base     string
+	dironly  bool
+	negate   bool
+	pattern  string
+}
+
+// NewSourceCollector takes the extensions of source files, which default to
+// "sol" and "vy", and returns a SourceCollector honoring .gitignore files and
+// excluding installed dependencies
+func NewSourceCollector(extensions ...string) *SourceCollector {
+	if len(extensions) == 0 {
+		extensions = []string{"sol", "vy"}
+	}
+	return &SourceCollector{
+		Exclude:    []string{solcutils.DependencyDir, ".git"},
+		Extensions: extensions,
+		GitIgnore:  true,
+	}
+}
+
+// Collect walks the project directory and returns the slash separated paths of
+// the source files relative to it, sorted
+func (sc *SourceCollector) Collect(projectdir string) (files []string, err error) {
+	if projectdir == "" {
+		projectdir = "."
+	}
+	err = sc.walk(projectdir, "", nil, &files)
+	sort.Strings(files)
+	return
+}
+
+func (sc *SourceCollector) walk(projectdir string, rel string, rules []*ignoreRule, files *[]string) (err error) {
+	dir := filepath.Join(projectdir, filepath.FromSlash(rel))
+	if sc.GitIgnore {
+		var retErr error
+		if rules, retErr = readGitIgnore(dir, rel, rules); retErr != nil {
+			err = retErr
+			return
+		}
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		err = fmt.Errorf("Could not read directory '%v': '%v'", dir, err)
+		return
+	}
+	for _, e := range entries {
+		p := path.Join(rel, e.Name())
+		if matchAny(sc.Exclude, p, e.IsDir()) || ignored(rules, p, e.IsDir()) {
+			continue
+		}
+		if e.IsDir() {
+			if err = sc.walk(projectdir, p, rules, files); err != nil {
+				return
+			}
+			continue
+		}
+		if !sc.hasExtension(e.Name()) {
+			continue
+		}
+		if (len(sc.Include) > 0) && !matchAny(sc.Include, p, false) {
+			continue
+		}
+		*files = append(*files, p)
+	}
+	return
+}
+
+func (sc *SourceCollector) hasExtension(name string) bool {
+	for _, ext := range sc.Extensions {
+		if strings.HasSuffix(name, "."+strings.TrimPrefix(ext, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSources collects the sources of the project directory, which can be empty
+// for the current working directory, and adds them to the manifest keyed by
+// their path relative to it, such as "./contracts/sub/X.sol". Sources are
+// inlined if inline is true, otherwise their relative path is added. The
+// keccak256 checksum of every source is recorded in SourceChecksums.
+func (p *PackageManifest) AddSources(projectdir string, sc *SourceCollector, inline bool) (err error) {
+	if sc == nil {
+		sc = NewSourceCollector()
+	}
+	files, err := sc.Collect(projectdir)
+	if err != nil {
+		return
+	}
+	return p.addSourceFiles(projectdir, files, "./", inline)
+}
+
+// addSourceFiles adds the files, relative to dir, under keys with the prefix
+func (p *PackageManifest) addSourceFiles(dir string, files []string, prefix string, inline bool) (err error) {
+	if len(p.Sources) == 0 {
+		p.Sources = make(map[string]string)
+	}
+	for _, f := range files {
+		b, retErr := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(f)))
+		if retErr != nil {
+			err = fmt.Errorf("Could not get read %v: '%v'", f, retErr)
+			return
+		}
+		key := prefix + f
+		if inline {
+			p.Sources[key] = string(b)
+		} else {
+			p.Sources[key] = key
+		}
+		p.AddSourceChecksum(key, b, ChecksumKeccak256)
+	}
+	return
+}
+
+// readGitIgnore appends the rules of the .gitignore file of the directory, if
+// there is one, to the rules of its parents
+func readGitIgnore(dir string, rel string, rules []*ignoreRule) ([]*ignoreRule, error) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return rules, nil
+	}
+	if err != nil {
+		return rules, fmt.Errorf("Could not read .gitignore in '%v': '%v'", dir, err)
+	}
+	defer f.Close()
+	// Copy so sibling directories do not share appended rules
+	rules = append([]*ignoreRule(nil), rules...)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if (line == "") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := &ignoreRule{base: rel}
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dironly, line = true, strings.TrimSuffix(line, "/")
+		}
+		r.anchored = strings.Contains(line, "/")
+		r.pattern = strings.TrimPrefix(line, "/")
+		rules = append(rules, r)
+	}
+	return rules, s.Err()
+}
+
+// ignored reports whether the last .gitignore rule matching the path ignores it
+func ignored(rules []*ignoreRule, p string, isdir bool) (ignore bool) {
+	for _, r := range rules {
+		if r.dironly && !isdir {
+			continue
+		}
+		rel := p
+		if r.base != "" {
+			if !strings.HasPrefix(p, r.base+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(p, r.base+"/")
+		}
+		var matched bool
+		if r.anchored {
+			matched = matchGlob(r.pattern, rel)
+		} else {
+			matched = matchGlob(r.pattern, path.Base(rel))
+		}
+		if matched {
+			ignore = !r.negate
+		}
+	}
+	return
+}
+
+// matchAny reports whether the path matches one of the patterns. Patterns
+// without a slash match any name along the path, and other patterns also match
+// the files below a directory they match.
+func matchAny(patterns []string, p string, isdir bool) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.TrimSuffix(pattern, "/"), "./")
+		if !strings.Contains(pattern, "/") {
+			for _, name := range strings.Split(p, "/") {
+				if matchGlob(pattern, name) {
+					return true
+				}
+			}
+			continue
+		}
+		if matchGlob(pattern, p) || (!isdir && matchGlob(pattern+"/**", p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash separated path against a glob where "**" matches
+// any number of path segments
+func matchGlob(pattern string, p string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(pattern []string, p []string) bool {
+	if len(pattern) == 0 {
+		return len(p) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(p); i++ {
+			if matchSegments(pattern[1:], p[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(p) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], p[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], p[1:])
+}
@@ -0,0 +1,85 @@
+package ethpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestFiles writes the files, keyed by slash separated path, to dir
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for k, v := range files {
+		path := filepath.Join(dir, filepath.FromSlash(k))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(v), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSourceCollector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"A.sol":                              "contract A {}",
+		"B.vy":                               "# vyper",
+		"README.md":                          "readme",
+		"x":                                  "short name",
+		"contracts/sub/X.sol":                "contract X {}",
+		"contracts/mocks/MockX.sol":          "contract MockX {}",
+		"contracts/build/Out.sol":            "contract Out {}",
+		"contracts/.gitignore":               "build/\n*.tmp.sol\n",
+		"contracts/Scratch.tmp.sol":          "contract Scratch {}",
+		"ethpm-dependencies/owned/Owned.sol": "contract Owned {}",
+	}
+	writeTestFiles(t, dir, files)
+
+	sc := NewSourceCollector()
+	got, err := sc.Collect(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"A.sol", "B.vy", "contracts/mocks/MockX.sol", "contracts/sub/X.sol"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	sc.Include = []string{"contracts/**"}
+	sc.Exclude = append(sc.Exclude, "contracts/mocks")
+	if got, err = sc.Collect(dir); err != nil {
+		t.Fatal(err)
+	}
+	if want = []string{"contracts/sub/X.sol"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	sc = NewSourceCollector("sol")
+	sc.GitIgnore = false
+	sc.Exclude = []string{"**/Mock*.sol"}
+	if got, err = sc.Collect(dir); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"A.sol", "contracts/Scratch.tmp.sol", "contracts/build/Out.sol", "contracts/sub/X.sol",
+		"ethpm-dependencies/owned/Owned.sol"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	p := &PackageManifest{}
+	if err = p.AddSources(dir, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Sources["./contracts/sub/X.sol"]; got != "./contracts/sub/X.sol" {
+		t.Fatalf("Got '%v', expected '%v'", got, "./contracts/sub/X.sol")
+	}
+	if err = p.SourceChecksums["./contracts/sub/X.sol"].Verify([]byte("contract X {}")); err != nil {
+		t.Fatal(err)
+	}
+}
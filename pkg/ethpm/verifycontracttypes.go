@@ -0,0 +1,293 @@
+package ethpm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/compilerutils"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+	"github.com/ethpm/ethpm-go/pkg/solcutils"
+)
+
+// NotCompared is the match of a bytecode the contract type does not record
+const NotCompared bc.MatchMode = "not-compared"
+
+// ContractTypeVerdict is the result of recompiling a single contract type.
+// Deployment and Runtime hold the way each bytecode matched, empty if it did
+// not, and Error why the contract type could not be recompiled, if it could not.
+type ContractTypeVerdict struct {
+	Compiler     string
+	ContractType string
+	Deployment   bc.MatchMode
+	Error        string
+	Runtime      bc.MatchMode
+	Version      string
+}
+
+// Valid returns true if the contract type was recompiled and every bytecode it
+// records matched exactly or once the metadata is ignored, with at least one
+// bytecode compared
+func (v *ContractTypeVerdict) Valid() bool {
+	if (v.Error != "") || !verifiedMatch(v.Deployment) || !verifiedMatch(v.Runtime) {
+		return false
+	}
+	return (v.Deployment != NotCompared) || (v.Runtime != NotCompared)
+}
+
+// verifiedMatch returns true for the matches a verified bytecode may have
+func verifiedMatch(m bc.MatchMode) bool {
+	return (m == bc.MatchExact) || (m == bc.MatchIgnoreMetadata) || (m == NotCompared)
+}
+
+// String returns the verdict as a single line, such as
+// "Token: OK (deployment exact, runtime exact)"
+func (v *ContractTypeVerdict) String() string {
+	if v.Error != "" {
+		return fmt.Sprintf("%v: ERROR %v", v.ContractType, v.Error)
+	}
+	verdict := "OK"
+	if !v.Valid() {
+		verdict = "MISMATCH"
+	}
+	return fmt.Sprintf("%v: %v (deployment %v, runtime %v)", v.ContractType, verdict, matchString(v.Deployment), matchString(v.Runtime))
+}
+
+func matchString(m bc.MatchMode) string {
+	if m == "" {
+		return "differs"
+	}
+	return string(m)
+}
+
+// VerifyContractTypes takes the directory the manifest was read from, the IPFS
+// gateway, which can be nil for the default, and a solc manager, which can be
+// nil to always use the installed compiler. It extracts the sources of the
+// package and of its dependencies to a temporary directory, then recompiles
+// every contract type with the compiler, version and settings recorded in it,
+// installing the version with the manager if one is given. Both the deployment
+// and the runtime bytecode are compared with the recorded ones, ignoring the
+// values written into link references and falling back to ignoring the
+// metadata. It returns a verdict for every contract type, sorted by name.
+func (p *PackageManifest) VerifyContractTypes(manifestdir string, gateway *solcutils.MetadataGateway, manager *solcutils.SolcManager) (verdicts []*ContractTypeVerdict, err error) {
+	dir, err := ioutil.TempDir("", "ethpm-verify")
+	if err != nil {
+		err = fmt.Errorf("Could not create directory to extract sources to: '%v'", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	written, err := p.ExtractSources(dir, manifestdir, gateway)
+	if err != nil {
+		return
+	}
+	sources := make(map[string]string)
+	for _, w := range written {
+		if path.Base(w) == "ethpm.json" {
+			continue
+		}
+		b, retErr := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(w)))
+		if retErr != nil {
+			err = fmt.Errorf("Could not read extracted source '%v': '%v'", w, retErr)
+			return
+		}
+		sources[w] = string(b)
+	}
+	names := make([]string, 0, len(p.ContractTypes))
+	for k := range p.ContractTypes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	outputs := make(map[string]string)
+	failures := make(map[string]error)
+	for _, name := range names {
+		ct := p.ContractTypes[name]
+		v := &ContractTypeVerdict{ContractType: name}
+		verdicts = append(verdicts, v)
+		if ct.Compiler == nil {
+			v.Error = "No compiler information recorded"
+			continue
+		}
+		v.Compiler, v.Version = ct.Compiler.Name, ct.Compiler.Version
+		settings, retErr := json.Marshal(ct.Compiler.Settings)
+		if retErr != nil {
+			v.Error = fmt.Sprintf("Invalid compiler settings: '%v'", retErr)
+			continue
+		}
+		// Contract types compiled together are only compiled once
+		key := v.Compiler + "\x00" + v.Version + "\x00" + string(settings)
+		if _, ok := outputs[key]; !ok {
+			outputs[key], failures[key] = p.recompile(ct.Compiler, dir, sources, manager)
+		}
+		if failures[key] != nil {
+			v.Error = failures[key].Error()
+			continue
+		}
+		if retErr = compareContractType(v, ct, name, outputs[key]); retErr != nil {
+			v.Error = retErr.Error()
+		}
+	}
+	return
+}
+
+// recompile compiles the extracted sources with the compiler information of a
+// contract type and returns the standard output json
+func (p *PackageManifest) recompile(ci *bc.CompilerInformation, dir string, sources map[string]string, manager *solcutils.SolcManager) (stdoutjson string, err error) {
+	if ci.Name == "" {
+		err = errors.New("No compiler name recorded")
+		return
+	}
+	compiler, err := manager.Resolve(ci.Name, ci.Version)
+	if err != nil {
+		err = fmt.Errorf("Error getting compiler version '%v': '%v'", ci.Version, err)
+		return
+	}
+	c, err := compilerutils.NewCompiler(compiler, dir)
+	if err != nil {
+		return
+	}
+	if ci.Version != "" {
+		version, retErr := c.Version()
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		if !solcutils.VersionMatches(version, ci.Version) {
+			err = fmt.Errorf("Compiler '%v' is version '%v', expected '%v'", compiler, version, ci.Version)
+			return
+		}
+	}
+	settings := &solcutils.SolcSettings{}
+	if ci.Settings != nil {
+		b, _ := json.Marshal(ci.Settings)
+		if err = json.Unmarshal(b, settings); err != nil {
+			err = fmt.Errorf("Invalid compiler settings: '%v'", err)
+			return
+		}
+	}
+	settings.SetOutputSelection("evm.bytecode", "evm.deployedBytecode")
+	if _, stdoutjson, err = compileSources(c, dir, sources, p.dependencyNames(), settings); err != nil {
+		err = fmt.Errorf("Error compiling sources: '%v'", err)
+		return
+	}
+	err = compilerErrors(stdoutjson)
+	return
+}
+
+// compilerErrors returns the errors reported in a standard output json
+func compilerErrors(stdoutjson string) (err error) {
+	var output struct {
+		Errors []struct {
+			FormattedMessage string `json:"formattedMessage"`
+			Message          string `json:"message"`
+			Severity         string `json:"severity"`
+		} `json:"errors"`
+	}
+	if err = json.Unmarshal([]byte(stdoutjson), &output); err != nil {
+		err = fmt.Errorf("Error parsing compiler output: '%v'", err)
+		return
+	}
+	var messages []string
+	for _, e := range output.Errors {
+		if e.Severity != "error" {
+			continue
+		}
+		m := e.FormattedMessage
+		if m == "" {
+			m = e.Message
+		}
+		messages = append(messages, strings.TrimSpace(m))
+	}
+	if len(messages) > 0 {
+		err = fmt.Errorf("Compiler reported errors: '%v'", strings.Join(messages, "; "))
+	}
+	return
+}
+
+// compareContractType compares the bytecode of a contract type with the one of
+// the contract of the same name in the compiler output, taken from the source of
+// the compilation target of the recorded metadata if there is one
+func compareContractType(v *ContractTypeVerdict, ct *ethcontract.ContractType, name string, stdoutjson string) (err error) {
+	contractname := ct.ContractName
+	if contractname == "" {
+		contractname = name
+	}
+	sourcepath, err := compilationTarget(ct.Metadata, contractname)
+	if err != nil {
+		return
+	}
+	contractjson, err := contractOutput(stdoutjson, contractname, sourcepath)
+	if err != nil {
+		err = fmt.Errorf("Error getting contract '%v' from compiler output: '%v'", contractname, err)
+		return
+	}
+	if contractjson == "" {
+		err = fmt.Errorf("Contract '%v' not found in compiler output", contractname)
+		return
+	}
+	var contract struct {
+		EVM struct {
+			Bytecode         json.RawMessage `json:"bytecode"`
+			DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+		} `json:"evm"`
+	}
+	if err = json.Unmarshal([]byte(contractjson), &contract); err != nil {
+		err = fmt.Errorf("Error parsing contract '%v' from compiler output: '%v'", contractname, err)
+		return
+	}
+	if v.Deployment, err = compareBytecode(ct.DeploymentBytecode, contract.EVM.Bytecode); err != nil {
+		err = fmt.Errorf("Deployment bytecode: %v", err)
+		return
+	}
+	if v.Runtime, err = compareBytecode(ct.RuntimeBytecode, contract.EVM.DeployedBytecode); err != nil {
+		err = fmt.Errorf("Runtime bytecode: %v", err)
+	}
+	return
+}
+
+// compilationTarget returns the source the recorded metadata names as defining
+// the contract, or an empty string if no metadata is recorded
+func compilationTarget(metadata string, contractname string) (sourcepath string, err error) {
+	if metadata == "" {
+		return
+	}
+	cm, err := solcutils.ParseCompilerMetadata(metadata)
+	if err != nil {
+		err = fmt.Errorf("Error parsing recorded metadata of '%v': '%v'", contractname, err)
+		return
+	}
+	if cm.Settings == nil {
+		return
+	}
+	for k, v := range cm.Settings.CompilationTarget {
+		if v == contractname {
+			sourcepath = k
+		}
+	}
+	return
+}
+
+// compareBytecode compares a recorded bytecode with a standard json bytecode
+// object, exactly or ignoring the metadata, returning NotCompared if none is
+// recorded
+func compareBytecode(recorded *bc.UnlinkedBytecode, compiledjson json.RawMessage) (match bc.MatchMode, err error) {
+	if (recorded == nil) || (recorded.Bytecode == "") {
+		match = NotCompared
+		return
+	}
+	if len(compiledjson) == 0 {
+		err = errors.New("No bytecode in compiler output")
+		return
+	}
+	compiled := &bc.UnlinkedBytecode{}
+	if err = compiled.Build(string(compiledjson)); err != nil {
+		return
+	}
+	return compiled.CompareUnlinked(recorded, bc.MatchExact, bc.MatchIgnoreMetadata)
+}
@@ -0,0 +1,124 @@
+package ethpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+)
+
+func TestVerifyContractTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-verify-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := `{"contracts":{"contracts/Token.sol":{` +
+		`"Token":{"evm":{"bytecode":{"object":"6001"},"deployedBytecode":{"object":"6002"}}},` +
+		`"Other":{"evm":{"bytecode":{"object":"6001"},"deployedBytecode":{"object":"6002"}}}}}}`
+	script := "#!/bin/sh\nif [ \"$1\" = \"--version\" ]; then echo 'Version: 0.4.24+commit.e67f0147.Linux.g++'; exit 0; fi\n" +
+		"cat > /dev/null\necho '" + output + "'\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "solc"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	defer os.Setenv("PATH", path)
+
+	compiler := func(version string) *bc.CompilerInformation {
+		return &bc.CompilerInformation{
+			Name:     "solc",
+			Settings: map[string]interface{}{"optimizer": map[string]interface{}{"enabled": true, "runs": 200}},
+			Version:  version,
+		}
+	}
+	p := &PackageManifest{
+		Sources: map[string]string{"./contracts/Token.sol": "contract Token {}\n"},
+		ContractTypes: map[string]*ethcontract.ContractType{
+			"Token": {
+				Compiler:           compiler("0.4.24+commit.e67f0147"),
+				DeploymentBytecode: &bc.UnlinkedBytecode{Bytecode: "0x6001"},
+				RuntimeBytecode:    &bc.UnlinkedBytecode{Bytecode: "0x6002"},
+			},
+			"Other": {
+				Compiler:           compiler("0.4.24+commit.e67f0147"),
+				DeploymentBytecode: &bc.UnlinkedBytecode{Bytecode: "0x6001"},
+				RuntimeBytecode:    &bc.UnlinkedBytecode{Bytecode: "0x6003"},
+			},
+			"Release": {
+				ContractName:    "Token",
+				Compiler:        compiler("0.4.24"),
+				RuntimeBytecode: &bc.UnlinkedBytecode{Bytecode: "0x6002"},
+			},
+			"Pinned": {
+				Compiler:        compiler("0.5.0"),
+				RuntimeBytecode: &bc.UnlinkedBytecode{Bytecode: "0x6002"},
+			},
+			"Unknown": {RuntimeBytecode: &bc.UnlinkedBytecode{Bytecode: "0x6002"}},
+		},
+	}
+	verdicts, err := p.VerifyContractTypes(dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]*ContractTypeVerdict)
+	for _, v := range verdicts {
+		got[v.ContractType] = v
+	}
+	if v := got["Token"]; !v.Valid() || (v.Deployment != bc.MatchExact) || (v.Runtime != bc.MatchExact) {
+		t.Fatalf("Got '%v', expected Token to match exactly", v)
+	}
+	if v := got["Other"]; v.Valid() || (v.Deployment != bc.MatchExact) || (v.Runtime != "") {
+		t.Fatalf("Got '%v', expected the runtime bytecode of Other to differ", v)
+	}
+	if v := got["Release"]; !v.Valid() || (v.Deployment != NotCompared) || (v.Runtime != bc.MatchExact) {
+		t.Fatalf("Got '%v', expected Release to match a compiler of the same release", v)
+	}
+	if v := got["Pinned"]; v.Valid() || (v.Error == "") {
+		t.Fatalf("Got '%v', expected an error for a compiler of another version", v)
+	}
+	if v := got["Unknown"]; v.Valid() || (v.Error == "") {
+		t.Fatalf("Got '%v', expected an error for a contract type without compiler information", v)
+	}
+}
+
+func TestContractTypeVerdictValid(t *testing.T) {
+	tests := []struct {
+		deployment bc.MatchMode
+		runtime    bc.MatchMode
+		want       bool
+	}{
+		{bc.MatchExact, bc.MatchIgnoreMetadata, true},
+		{NotCompared, bc.MatchExact, true},
+		{NotCompared, NotCompared, false},
+		{bc.MatchExact, bc.MatchMetadataHash, false},
+		{bc.MatchExact, "", false},
+	}
+	for _, tt := range tests {
+		v := &ContractTypeVerdict{Deployment: tt.deployment, Runtime: tt.runtime}
+		if got := v.Valid(); got != tt.want {
+			t.Fatalf("Got '%v' for '%v', expected '%v'", got, v, tt.want)
+		}
+	}
+}
+
+func TestCompareContractTypeTarget(t *testing.T) {
+	output := `{"contracts":{` +
+		`"contracts/A.sol":{"Token":{"evm":{"deployedBytecode":{"object":"6001"}}}},` +
+		`"contracts/B.sol":{"Token":{"evm":{"deployedBytecode":{"object":"6002"}}}}}}`
+	ct := &ethcontract.ContractType{RuntimeBytecode: &bc.UnlinkedBytecode{Bytecode: "0x6002"}}
+	v := &ContractTypeVerdict{}
+	if err := compareContractType(v, ct, "Token", output); err == nil {
+		t.Fatalf("Got '%v', expected an error for a contract defined in two sources", err)
+	}
+	ct.Metadata = `{"settings":{"compilationTarget":{"contracts/B.sol":"Token"}}}`
+	if err := compareContractType(v, ct, "Token", output); err != nil {
+		t.Fatal(err)
+	}
+	if v.Runtime != bc.MatchExact {
+		t.Fatalf("Got '%v', expected '%v'", v.Runtime, bc.MatchExact)
+	}
+}
@@ -0,0 +1,80 @@
+package ethpm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/node"
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/gethutils"
+)
+
+// DeploymentVerification is the result of comparing the code of a deployed
+// contract instance with the runtime bytecode of its contract type. Match is
+// empty if the code does not match. Immutables holds the deployed value of
+// every immutable reference, keyed by its AST id.
+type DeploymentVerification struct {
+	Address      string
+	ContractType string
+	Immutables   map[string]string
+	Match        bc.MatchMode
+	Name         string
+}
+
+// VerifyDeployment takes a blockchain uri, or a prefix matching exactly one
+// blockchain uri of the deployments, the name of a contract instance deployed on
+// it, the project directory containing the installed build dependencies, which
+// can be empty for the working directory, and the backend to read its code from,
+// which can be nil to use the ipc connection of a geth node with the default
+// data directory. It compares the
+// deployed code with the runtime bytecode of the instance's contract type,
+// masking immutables and linked libraries, and reports the values of the
+// immutables.
+func (p *PackageManifest) VerifyDeployment(ctx context.Context,
+	blockchainuri string,
+	name string,
+	projectdir string,
+	backend bind.ContractCaller,
+) (v *DeploymentVerification, err error) {
+	chain, err := p.deploymentChain(blockchainuri)
+	if err != nil {
+		return
+	}
+	ci, ok := p.Deployments[chain][name]
+	if !ok {
+		err = fmt.Errorf("No deployment '%v' on '%v'", name, chain)
+		return
+	}
+	ct, err := p.ResolveContractType(ci.ContractType, projectdir)
+	if err != nil {
+		return
+	}
+	if (ct.RuntimeBytecode == nil) || (ct.RuntimeBytecode.Bytecode == "") {
+		err = fmt.Errorf("Contract type '%v' has no runtime_bytecode", ci.ContractType)
+		return
+	}
+	if backend == nil {
+		ec, _, retErr := gethutils.ConnectGeth(node.DefaultDataDir())
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		backend = ec
+	}
+	code, err := backend.CodeAt(ctx, common.HexToAddress(ci.Address), nil)
+	if err != nil {
+		err = fmt.Errorf("Error getting code of '%v': '%v'", ci.Address, err)
+		return
+	}
+	if len(code) == 0 {
+		err = fmt.Errorf("No code at '%v'", ci.Address)
+		return
+	}
+	v = &DeploymentVerification{Address: ci.Address, ContractType: ci.ContractType, Name: name}
+	if v.Match, v.Immutables, err = ct.RuntimeBytecode.VerifyDeployed(common.Bytes2Hex(code)); err != nil {
+		err = fmt.Errorf("Error verifying '%v': '%v'", name, err)
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package ethpm
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	bc "github.com/ethpm/ethpm-go/pkg/bytecode"
+	"github.com/ethpm/ethpm-go/pkg/ethcontract"
+)
+
+func TestVerifyDeployment(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	opts := bind.NewKeyedTransactor(key)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{opts.From: {Balance: big.NewInt(1000000000000000000)}}, 4712388)
+	// The constructor returns the runtime code with 42 as the immutable value
+	value := strings.Repeat("00", 31) + "2a"
+	runtime := "7f" + strings.Repeat("00", 32) + "60005260206000f3"
+	deployment := "602980600b6000396000f3" + "7f" + value + "60005260206000f3"
+	address, _, _, err := bind.DeployContract(opts, abi.ABI{}, common.FromHex(deployment), sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	p := &PackageManifest{PackageName: "answer"}
+	p.ContractTypes = map[string]*ethcontract.ContractType{
+		"Answer": {RuntimeBytecode: &bc.UnlinkedBytecode{
+			Bytecode:            "0x" + runtime,
+			ImmutableReferences: []*bc.ImmutableReference{{Length: 32, Name: "5", Offsets: []int{1}}},
+		}},
+	}
+	p.Deployments = map[string]map[string]*ethcontract.ContractInstance{
+		testChain: {"TheAnswer": {Address: address.Hex(), ContractType: "Answer"}},
+	}
+	v, err := p.VerifyDeployment(context.Background(), testChain, "TheAnswer", "", sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Match != bc.MatchExact {
+		t.Fatalf("Got '%v', expected '%v'", v.Match, bc.MatchExact)
+	}
+	if v.Immutables["5"] != "0x"+value {
+		t.Fatalf("Got '%v', expected '%v'", v.Immutables["5"], "0x"+value)
+	}
+
+	p.ContractTypes["Answer"].RuntimeBytecode.ImmutableReferences = nil
+	if v, err = p.VerifyDeployment(context.Background(), testChain, "TheAnswer", "", sim); err != nil {
+		t.Fatal(err)
+	}
+	if v.Match != "" {
+		t.Fatalf("Got '%v', expected no match without the immutable reference", v.Match)
+	}
+}
@@ -0,0 +1,254 @@
+package ethpm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version, ignoring build metadata
+type semver struct {
+	n   [3]int
+	pre string
+}
+
+// comparator is a single bound of a version range, such as ">=1.2.0"
+type comparator struct {
+	op string
+	v  semver
+}
+
+// MatchVersionRange takes a semantic version and a range in the npm style used
+// by ethpm projects, such as "^1.2.0", "~1.2", ">= 1.0.0 <2.0.0", "1.x",
+// "1.2.3 - 2.3" or "1.2.3 || ^2.0.0", and reports whether the version is in the
+// range
+func MatchVersionRange(version string, versionrange string) (ok bool, err error) {
+	v, parts, err := parseSemver(strings.TrimPrefix(strings.TrimSpace(version), "v"))
+	if (err == nil) && (parts < 3) {
+		err = fmt.Errorf("Version '%v' is not a full semantic version", version)
+	}
+	if err != nil {
+		return
+	}
+	for _, set := range strings.Split(versionrange, "||") {
+		comparators, retErr := parseRangeSet(set)
+		if retErr != nil {
+			err = fmt.Errorf("Invalid version range '%v': '%v'", versionrange, retErr)
+			return
+		}
+		match := true
+		for _, c := range comparators {
+			if !c.match(v) {
+				match = false
+				break
+			}
+		}
+		if match {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// parseRangeSet parses space separated comparators into bounds which must all
+// match. An operator may be separated from its version, as in ">= 1.0.0", and a
+// hyphen range such as "1.2.3 - 2.3.4" includes both of its ends.
+func parseRangeSet(set string) (comparators []*comparator, err error) {
+	fields := strings.Fields(set)
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "-" {
+			err = fmt.Errorf("Hyphen range in '%v' is missing an end", set)
+			return
+		}
+		var bounds []*comparator
+		if (i+1 < len(fields)) && (fields[i+1] == "-") {
+			if i+2 == len(fields) {
+				err = fmt.Errorf("Hyphen range in '%v' is missing an end", set)
+				return
+			}
+			bounds, err = parseHyphenRange(fields[i], fields[i+2])
+			i += 2
+		} else {
+			op, field := splitOperator(fields[i])
+			if (op != "") && (field == "") {
+				if i+1 == len(fields) {
+					err = fmt.Errorf("Operator '%v' in '%v' has no version", op, set)
+					return
+				}
+				i++
+				field = fields[i]
+			}
+			bounds, err = parseComparator(op, field)
+		}
+		if err != nil {
+			return
+		}
+		comparators = append(comparators, bounds...)
+	}
+	return
+}
+
+// splitOperator splits the operator, if any, from the start of a comparator
+func splitOperator(field string) (op string, rest string) {
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(field, prefix) {
+			return prefix, field[len(prefix):]
+		}
+	}
+	return "", field
+}
+
+// parseHyphenRange parses the ends of a hyphen range. A partial lower end is
+// filled with zeros and a partial upper end includes every version it matches.
+func parseHyphenRange(from string, to string) (comparators []*comparator, err error) {
+	lower, _, err := parseSemver(strings.TrimPrefix(from, "v"))
+	if err != nil {
+		return
+	}
+	upper, parts, err := parseSemver(strings.TrimPrefix(to, "v"))
+	if err != nil {
+		return
+	}
+	comparators = append(comparators, &comparator{op: ">=", v: lower})
+	switch {
+	case parts == 0:
+	case parts < 3:
+		comparators = append(comparators, &comparator{op: "<", v: upper.bump(parts - 1)})
+	default:
+		comparators = append(comparators, &comparator{op: "<=", v: upper})
+	}
+	return
+}
+
+// parseComparator parses a single operator and version into the bounds it sets
+func parseComparator(op string, field string) (comparators []*comparator, err error) {
+	v, parts, err := parseSemver(strings.TrimPrefix(field, "v"))
+	if err != nil {
+		return
+	}
+	if parts == 0 {
+		// "*" or "x" match every version
+		return
+	}
+	lower := &comparator{op: ">=", v: v}
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			comparators = append(comparators, &comparator{op: "=", v: v})
+			return
+		}
+		comparators = append(comparators, lower, &comparator{op: "<", v: v.bump(parts - 1)})
+	case "^":
+		// The first non-zero part, or the last given part, may not change
+		i := 0
+		for (i < parts-1) && (v.n[i] == 0) {
+			i++
+		}
+		comparators = append(comparators, lower, &comparator{op: "<", v: v.bump(i)})
+	case "~":
+		i := 1
+		if parts == 1 {
+			i = 0
+		}
+		comparators = append(comparators, lower, &comparator{op: "<", v: v.bump(i)})
+	case ">":
+		if parts < 3 {
+			comparators = append(comparators, &comparator{op: ">=", v: v.bump(parts - 1)})
+			return
+		}
+		comparators = append(comparators, &comparator{op: op, v: v})
+	case "<=":
+		if parts < 3 {
+			comparators = append(comparators, &comparator{op: "<", v: v.bump(parts - 1)})
+			return
+		}
+		comparators = append(comparators, &comparator{op: op, v: v})
+	default:
+		comparators = append(comparators, &comparator{op: op, v: v})
+	}
+	return
+}
+
+func (c *comparator) match(v semver) bool {
+	cmp := v.compare(c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// parseSemver parses a possibly partial version such as "1", "1.2", "1.x" or
+// "1.2.3-beta.1" and returns the number of numeric parts given
+func parseSemver(s string) (v semver, parts int, err error) {
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		s, v.pre = s[:i], s[i+1:]
+	}
+	if s == "" {
+		return
+	}
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 {
+		err = fmt.Errorf("Version '%v' has too many parts", s)
+		return
+	}
+	for i, f := range fields {
+		if (f == "x") || (f == "X") || (f == "*") {
+			break
+		}
+		n, retErr := strconv.Atoi(f)
+		if (retErr != nil) || (n < 0) {
+			err = fmt.Errorf("Version '%v' has an invalid part '%v'", s, f)
+			return
+		}
+		v.n[i] = n
+		parts++
+	}
+	return
+}
+
+// bump returns the lowest version above every version sharing the parts up to
+// and including the given one
+func (v semver) bump(part int) (b semver) {
+	for i := 0; i < part; i++ {
+		b.n[i] = v.n[i]
+	}
+	b.n[part] = v.n[part] + 1
+	return
+}
+
+// compare returns -1, 0 or 1 as v is lower than, equal to or higher than o.
+// Pre-releases are lower than the release and compared as strings otherwise.
+func (v semver) compare(o semver) int {
+	for i := range v.n {
+		if v.n[i] != o.n[i] {
+			if v.n[i] < o.n[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.pre == o.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case o.pre == "":
+		return -1
+	case v.pre < o.pre:
+		return -1
+	}
+	return 1
+}
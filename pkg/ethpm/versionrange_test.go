@@ -0,0 +1,57 @@
+package ethpm
+
+import "testing"
+
+func TestMatchVersionRange(t *testing.T) {
+	tests := []struct {
+		version      string
+		versionrange string
+		want         bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2.4", "1.2.3", false},
+		{"1.9.0", "^1.2.3", true},
+		{"2.0.0", "^1.2.3", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.9.9", "~1", true},
+		{"1.4.0", "1.x", true},
+		{"2.0.0", "1.x", false},
+		{"1.2.0", ">=1.0.0 <2.0.0", true},
+		{"2.0.0", ">=1.0.0 <2.0.0", false},
+		{"1.3.0", ">1.2", true},
+		{"1.2.9", ">1.2", false},
+		{"1.2.9", "<=1.2", true},
+		{"2.1.0", "1.2.3 || ^2.0.0", true},
+		{"1.0.0-beta.1", ">=1.0.0", false},
+		{"5.0.0", "*", true},
+		{"2.0.0", ">= 1.0.0", true},
+		{"0.9.0", ">= 1.0.0", false},
+		{"1.5.0", ">= 1.0.0 < 2.0.0", true},
+		{"2.0.0", ">= 1.0.0 < 2.0.0", false},
+		{"1.2.3", "1.2.3 - 2.3.4", true},
+		{"2.3.4", "1.2.3 - 2.3.4", true},
+		{"2.3.5", "1.2.3 - 2.3.4", false},
+		{"1.2.2", "1.2.3 - 2.3.4", false},
+		{"2.3.9", "1.2 - 2.3", true},
+		{"2.4.0", "1.2 - 2.3", false},
+		{"3.0.0", "1.2.3 - 2.3.4 || >= 3", true},
+	}
+	for _, tt := range tests {
+		got, err := MatchVersionRange(tt.version, tt.versionrange)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("Got '%v' for '%v' in '%v', expected '%v'", got, tt.version, tt.versionrange, tt.want)
+		}
+	}
+	for _, versionrange := range []string{"^one", ">=", "1.2.3 -", "- 2.0.0"} {
+		if _, err := MatchVersionRange("1.2.3", versionrange); err == nil {
+			t.Fatalf("Got '%v', expected an error for the invalid range '%v'", err, versionrange)
+		}
+	}
+}
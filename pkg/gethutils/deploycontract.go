@@ -0,0 +1,110 @@
+package gethutils
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Deployer sends contract creation transactions through the ipc connection of
+// a locally running geth node, signing them with an account from the local keystore.
+type Deployer struct {
+	Account   accounts.Account
+	Client    *ethclient.Client
+	GasPrice  *big.Int
+	KeyStore  *keystore.KeyStore
+	NetworkID *big.Int
+	Password  string
+	rpc       *rpc.Client
+}
+
+// NewDeployer takes the geth data directory, the wallet address you wish to use
+// in the local keystore and the preferred gas price, then connects to geth and
+// prompts for the key password. If gaspriceinwei is 0 the suggested gas price
+// is used for every deployment.
+func NewDeployer(gethdatadir string, fromaddressashex string, gaspriceinwei int64) (d *Deployer, err error) {
+	d = &Deployer{}
+	if d.rpc, err = rpc.Dial(gethdatadir + "/geth.ipc"); err != nil {
+		err = fmt.Errorf("Could not find geth connection to '%v': '%v'", gethdatadir+"/geth.ipc", err)
+		return
+	}
+	d.Client = ethclient.NewClient(d.rpc)
+	if d.NetworkID, err = d.Client.NetworkID(context.Background()); err != nil {
+		err = fmt.Errorf("Could not get network id: '%v'", err)
+		return
+	}
+	if d.KeyStore, d.Account, err = GetAccountByAddress(common.HexToAddress(fromaddressashex), gethdatadir); err != nil {
+		err = fmt.Errorf("Error getting wallet: '%v'", err)
+		return
+	}
+	if gaspriceinwei != 0 {
+		d.GasPrice = big.NewInt(gaspriceinwei)
+	}
+	d.Password = GetPassword()
+	return
+}
+
+// DeployContract sends the given linked deployment bytecode in a contract creation
+// transaction and waits for it to be mined. It returns the new contract address
+// with the transaction and block hashes.
+func (d *Deployer) DeployContract(bytecode string) (address string, transaction string, block string, err error) {
+	ctx := context.Background()
+	data := common.FromHex(bytecode)
+
+	nonce, err := d.Client.PendingNonceAt(ctx, d.Account.Address)
+	if err != nil {
+		err = fmt.Errorf("Error getting wallet nonce: '%v'", err)
+		return
+	}
+	gasprice := d.GasPrice
+	if gasprice == nil {
+		if gasprice, err = d.Client.SuggestGasPrice(ctx); err != nil {
+			err = fmt.Errorf("Error getting suggested gas: '%v'", err)
+			return
+		}
+	}
+	gas, err := d.Client.EstimateGas(ctx, ethereum.CallMsg{From: d.Account.Address, Data: data})
+	if err != nil {
+		err = fmt.Errorf("Error estimating gas: '%v'", err)
+		return
+	}
+	tx := types.NewContractCreation(nonce, big.NewInt(0), gas, gasprice, data)
+	stx, err := d.KeyStore.SignTxWithPassphrase(d.Account, d.Password, tx, d.NetworkID)
+	if err != nil {
+		err = fmt.Errorf("Signing failed: '%v'", err)
+		return
+	}
+	if err = d.Client.SendTransaction(ctx, stx); err != nil {
+		err = fmt.Errorf("Error sending transaction: '%v'", err)
+		return
+	}
+	receipt, err := bind.WaitMined(ctx, d.Client, stx)
+	if err != nil {
+		err = fmt.Errorf("Error waiting for transaction '%v': '%v'", stx.Hash().Hex(), err)
+		return
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		err = fmt.Errorf("Contract creation transaction '%v' failed", stx.Hash().Hex())
+		return
+	}
+	var r struct {
+		BlockHash string `json:"blockHash"`
+	}
+	if err = d.rpc.CallContext(ctx, &r, "eth_getTransactionReceipt", stx.Hash()); err != nil {
+		err = fmt.Errorf("Error getting transaction block: '%v'", err)
+		return
+	}
+	address = receipt.ContractAddress.Hex()
+	transaction = stx.Hash().Hex()
+	block = r.BlockHash
+	return
+}
@@ -0,0 +1,42 @@
+package gethutils
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeystoreTransactor takes the path of an encrypted keystore file and its
+// password, then returns the options used to sign transactions with that key
+func KeystoreTransactor(keyfile string, password string) (opts *bind.TransactOpts, err error) {
+	b, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		err = fmt.Errorf("Could not read keystore file: '%v'", err)
+		return
+	}
+	if opts, err = bind.NewTransactor(bytes.NewReader(b), password); err != nil {
+		err = fmt.Errorf("Could not decrypt keystore file '%v': '%v'", keyfile, err)
+	}
+	return
+}
+
+// RawKeyTransactor takes the path of a file holding a hex encoded private key
+// and returns the options used to sign transactions with that key
+func RawKeyTransactor(keyfile string) (opts *bind.TransactOpts, err error) {
+	b, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		err = fmt.Errorf("Could not read key file: '%v'", err)
+		return
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"))
+	if err != nil {
+		err = fmt.Errorf("Invalid private key in '%v': '%v'", keyfile, err)
+		return
+	}
+	opts = bind.NewKeyedTransactor(key)
+	return
+}
@@ -20,13 +20,15 @@ package githubutils
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 )
 
-// GetCommit takes a local repository directory and branch, default is 'master' if an
-// empty string, and return the latest commit hash
+// GetCommit takes a local repository directory, bare or not, and branch, default
+// is 'master' if an empty string, and return the latest commit hash. Loose and
+// packed refs are both read.
 func GetCommit(packagedir string, branch string) (commit string, err error) {
-	var commithashpath string
 	if packagedir == "" {
 		if packagedir, err = os.Getwd(); err != nil {
 			err = fmt.Errorf("Could not get working directory: '%v'", err)
@@ -36,20 +38,16 @@ func GetCommit(packagedir string, branch string) (commit string, err error) {
 	if branch == "" {
 		branch = "master"
 	}
-	commithashpath = packagedir + "/.git/refs/heads/" + branch
-	file, err := os.Open(filepath.FromSlash(commithashpath))
+	repo, err := git.PlainOpen(packagedir)
 	if err != nil {
-		err = fmt.Errorf("Could not open file '%v': '%v'", commithashpath, err)
+		err = fmt.Errorf("Could not open repository '%v': '%v'", packagedir, err)
 		return "", err
 	}
-	info, _ := file.Stat()
-	commitbytes := make([]byte, info.Size())
-	_, err = file.Read(commitbytes)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
 	if err != nil {
-		err = fmt.Errorf("Could not read file '%v': '%v'", commithashpath, err)
+		err = fmt.Errorf("Could not find branch '%v': '%v'", branch, err)
 		return "", err
 	}
-	commitbytes = commitbytes[:len(commitbytes)-1]
-	commit = string(commitbytes)
+	commit = ref.Hash().String()
 	return
 }
@@ -2,25 +2,37 @@ package githubutils
 
 import (
 	"fmt"
-	"net/url"
+	"io/ioutil"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
+
+	git "gopkg.in/src-d/go-git.v4"
 )
 
-// GetETHPMManifest takes a complete github commit uri, and clones it into the
-// given parent directory. If parentdir is an empty string, it will clone into
-// the current working directory, then go into this directory and retrieve
-// the ethpm manifest by looking for an 'ethpm.json' file.
+// GetETHPMManifest takes a git uri of a commit, a directory or a manifest file,
+// see GitURI, and clones the repository into the given parent directory. If
+// parentdir is an empty string, it will clone into the current working directory.
+// The clone is checked out at the commit of the uri and the manifest is read
+// from it, 'ethpm.json' at the root of a commit uri or in the directory of a
+// tree uri. An existing clone is fetched into, and refused if it has another
+// origin or uncommitted changes. Local repositories are read in place without
+// being cloned.
 func GetETHPMManifest(commithttpsurl string, parentdir string) (jsonstring string, err error) {
-	uri, err := url.Parse(commithttpsurl)
-	uripart := strings.Split(uri.Path, "/")
-	project := uripart[len(uripart)-3]
-	commit := uripart[len(uripart)-1]
-	newuri := strings.Join(uripart[:len(uripart)-2], "/")
-	uri.Path = newuri
-
+	gu, err := ParseGitURI(commithttpsurl)
+	if err != nil {
+		return
+	}
+	if filepath.IsAbs(gu.Repository) {
+		b, retErr := ReadGitURI(commithttpsurl)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		jsonstring = string(b)
+		return
+	}
 	if parentdir == "" {
 		parentdir, err = os.Getwd()
 		if err != nil {
@@ -28,36 +40,56 @@ func GetETHPMManifest(commithttpsurl string, parentdir string) (jsonstring strin
 			return
 		}
 	}
-	repodir := filepath.FromSlash(parentdir + "/" + project)
-	gitlocation, err := exec.LookPath("git")
+	project := strings.TrimSuffix(path.Base(gu.Repository), ".git")
+	repodir := filepath.Join(parentdir, project)
+	repo, err := gu.cloneTo(repodir)
 	if err != nil {
-		err = fmt.Errorf("Error getting git bin location: '%v'", err)
+		err = fmt.Errorf("Error cloning '%v': '%v'", gu.Repository, err)
 		return
 	}
-	gitCmd := exec.Command(gitlocation, "clone", uri.String(), repodir)
-	_, err = gitCmd.Output()
+	commit, manifestpath, err := gu.Resolve(repo)
 	if err != nil {
-		err = fmt.Errorf("Error calling git command: '%v'", err)
+		return
 	}
-
-	gitCmd = exec.Command(gitlocation, "-C", repodir, "checkout", commit)
-	_, err = gitCmd.Output()
+	wt, err := repo.Worktree()
 	if err != nil {
-		err = fmt.Errorf("Error calling git command: '%v'", err)
+		return
 	}
-	ethpmfile := filepath.Join(repodir, "ethpm.json")
-	file, err := os.Open(ethpmfile)
-	if err != nil {
-		err = fmt.Errorf("Could not open ethpm json file: '%v'", err)
+	if err = checkClean(wt, repodir); err != nil {
 		return
 	}
-	info, _ := file.Stat()
-	manifestbytes := make([]byte, info.Size())
-	_, err = file.Read(manifestbytes)
+	if err = wt.Checkout(&git.CheckoutOptions{Hash: commit.Hash}); err != nil {
+		err = fmt.Errorf("Error checking out '%v': '%v'", commit.Hash, err)
+		return
+	}
+	if gu.Kind != GitBlob {
+		manifestpath = path.Join(manifestpath, ManifestFile)
+	}
+	ethpmfile := filepath.Join(repodir, filepath.FromSlash(manifestpath))
+	manifestbytes, err := ioutil.ReadFile(ethpmfile)
 	if err != nil {
-		err = fmt.Errorf("Could not read ethpmfile: '%v'", err)
+		err = fmt.Errorf("Could not read ethpm json file: '%v'", err)
 		return
 	}
 	jsonstring = string(manifestbytes)
 	return
 }
+
+// checkClean returns an error if the worktree has staged or unstaged changes to
+// tracked files, which checking out another commit would overwrite
+func checkClean(wt *git.Worktree, dir string) (err error) {
+	status, err := wt.Status()
+	if err != nil {
+		return
+	}
+	for file, s := range status {
+		if s.Worktree == git.Untracked {
+			continue
+		}
+		if (s.Staging != git.Unmodified) || (s.Worktree != git.Unmodified) {
+			err = fmt.Errorf("Repository '%v' has uncommitted changes to '%v'", dir, file)
+			return
+		}
+	}
+	return
+}
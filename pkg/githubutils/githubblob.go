@@ -0,0 +1,124 @@
+package githubutils
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// GithubAPI is the root of the GitHub api blob uris are created for
+const GithubAPI = "https://api.github.com"
+
+var blobPathRegex = regexp.MustCompile(`^/repos/([^/]+)/([^/]+)/git/blobs/([0-9a-fA-F]{40})$`)
+
+// GitBlobHash returns the hash git gives the content as a blob, the hex encoded
+// sha1 of "blob <length>\0" followed by the content
+func GitBlobHash(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CreateGithubBlobURI takes the account, the repository and the content of a
+// file committed to it, and returns the content addressed GitHub api uri of
+// the blob, such as https://api.github.com/repos/<account>/<repo>/git/blobs/<sha>
+func CreateGithubBlobURI(account string, repo string, content []byte) string {
+	return GithubAPI + "/repos/" + account + "/" + repo + "/git/blobs/" + GitBlobHash(content)
+}
+
+// ParseGithubBlobURI takes a GitHub api blob uri and returns the account, the
+// repository and the blob hash. Any host is accepted, so GitHub Enterprise and
+// test servers can be used.
+func ParseGithubBlobURI(uri string) (account string, repo string, blobhash string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		err = fmt.Errorf("Error parsing uri: '%v'", err)
+		return
+	}
+	m := blobPathRegex.FindStringSubmatch(u.Path)
+	if ((u.Scheme != "http") && (u.Scheme != "https")) || (m == nil) {
+		err = fmt.Errorf("'%v' is not a GitHub blob uri", uri)
+		return
+	}
+	account, repo, blobhash = m[1], m[2], strings.ToLower(m[3])
+	return
+}
+
+// IsGithubBlobURI returns true if the uri is a GitHub api blob uri
+func IsGithubBlobURI(uri string) bool {
+	_, _, _, err := ParseGithubBlobURI(uri)
+	return err == nil
+}
+
+// VerifyGitBlob checks the content against a git blob hash
+func VerifyGitBlob(content []byte, blobhash string) (err error) {
+	if got := GitBlobHash(content); got != strings.ToLower(blobhash) {
+		err = fmt.Errorf("Git blob hash is '%v', expected '%v'", got, blobhash)
+	}
+	return
+}
+
+// httpClient fetches blobs, giving up on hosts which do not respond
+var httpClient = &http.Client{Timeout: time.Minute}
+
+// ReadGithubBlobURI fetches the blob of a GitHub api blob uri, decodes its
+// content and verifies it against the blob hash in the uri
+func ReadGithubBlobURI(uri string) (content []byte, err error) {
+	_, _, blobhash, err := ParseGithubBlobURI(uri)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Got status '%v' for '%v'", resp.Status, uri)
+		return
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	var blob struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err = json.Unmarshal(b, &blob); err != nil {
+		err = fmt.Errorf("Error parsing blob '%v': '%v'", uri, err)
+		return
+	}
+	switch blob.Encoding {
+	case "base64":
+		// GitHub wraps the encoded content in lines
+		if content, err = base64.StdEncoding.DecodeString(strings.Replace(blob.Content, "\n", "", -1)); err != nil {
+			err = fmt.Errorf("Error decoding blob '%v': '%v'", uri, err)
+			return
+		}
+	case "utf-8":
+		content = []byte(blob.Content)
+	default:
+		err = fmt.Errorf("Unsupported encoding '%v' of blob '%v'", blob.Encoding, uri)
+		return
+	}
+	if err = VerifyGitBlob(content, blobhash); err != nil {
+		content = nil
+		err = fmt.Errorf("Blob '%v' does not match: '%v'", uri, err)
+	}
+	return
+}
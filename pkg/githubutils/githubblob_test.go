@@ -0,0 +1,86 @@
+package githubutils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitBlobHash(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"", "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+		{"hello world\n", "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"},
+	}
+	for _, tt := range tests {
+		if got := GitBlobHash([]byte(tt.content)); got != tt.want {
+			t.Fatalf("Got '%v', expected '%v'", got, tt.want)
+		}
+	}
+	got := CreateGithubBlobURI("ethpm", "token", []byte("hello world\n"))
+	want := "https://api.github.com/repos/ethpm/token/git/blobs/3b18e512dba79e4c8300dd08aeb37f8e728b8dad"
+	if got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if IsGithubBlobURI("https://github.com/ethpm/token/blob/master/Token.sol") {
+		t.Fatalf("Got '%v', expected a blob page not to be a blob uri", true)
+	}
+}
+
+func TestReadGithubBlobURI(t *testing.T) {
+	content := "contract Token {}\n"
+	blobs := map[string]string{
+		"/repos/ethpm/token/git/blobs/" + GitBlobHash([]byte(content)): content,
+		// Served content which does not match the hash in the uri
+		"/repos/ethpm/token/git/blobs/" + GitBlobHash([]byte("contract Owned {}\n")): content,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, ok := blobs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte(c))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"content":  encoded[:8] + "\n" + encoded[8:],
+			"encoding": "base64",
+			"size":     len(c),
+		})
+	}))
+	defer server.Close()
+
+	uri := server.URL + "/repos/ethpm/token/git/blobs/" + GitBlobHash([]byte(content))
+	got, err := ReadGithubBlobURI(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Fatalf("Got '%v', expected '%v'", string(got), content)
+	}
+	tampered := server.URL + "/repos/ethpm/token/git/blobs/" + GitBlobHash([]byte("contract Owned {}\n"))
+	if _, err = ReadGithubBlobURI(tampered); (err == nil) || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("Got '%v', expected a mismatch for tampered content", err)
+	}
+	if _, err = ReadGithubBlobURI(server.URL + "/repos/ethpm/token/git/blobs/" + strings.Repeat("0", 40)); err == nil {
+		t.Fatalf("Got '%v', expected an error for a missing blob", err)
+	}
+
+	hung := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hung
+	}))
+	defer slow.Close()
+	defer close(hung)
+	timeout := httpClient.Timeout
+	httpClient.Timeout = 10 * time.Millisecond
+	defer func() { httpClient.Timeout = timeout }()
+	if _, err = ReadGithubBlobURI(slow.URL + "/repos/ethpm/token/git/blobs/" + GitBlobHash([]byte(content))); err == nil {
+		t.Fatalf("Got '%v', expected a timeout from an unresponsive host", err)
+	}
+}
@@ -0,0 +1,286 @@
+package githubutils
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// Kinds of git uris
+const (
+	GitBlob   = "blob"
+	GitCommit = "commit"
+	GitTree   = "tree"
+)
+
+// ManifestFile is the name of the manifest read from commit and tree uris
+const ManifestFile = "ethpm.json"
+
+// fetchRefSpecs fetch every branch as a remote-tracking branch, leaving the
+// local branches of an existing clone untouched, and every tag not already
+// present
+var fetchRefSpecs = []config.RefSpec{
+	config.RefSpec("+refs/heads/*:refs/remotes/" + git.DefaultRemoteName + "/*"),
+	"refs/tags/*:refs/tags/*",
+}
+
+// GitURI is a uri of a commit, a directory or a file in a git repository, in the
+// form used by GitHub, GitLab and most other hosts:
+//
+//	https://github.com/<account>/<repo>/commit/<sha>
+//	https://gitlab.com/<account>/<repo>/-/tree/<ref>/<path>
+//	https://example.com/<account>/<repo>/blob/<ref>/<path>
+//
+// Local repositories, bare or not, are given as file uris, such as
+// file:///srv/git/token.git/blob/v1.0.0/contracts/Token.sol. The ref is a
+// branch, a tag or a full commit hash, and may contain slashes.
+type GitURI struct {
+	Kind       string
+	Repository string
+	RefPath    string
+}
+
+// ParseGitURI takes a git uri and returns the GitURI struct. The kind is the
+// first 'blob', 'tree' or 'commit' segment following the repository.
+func ParseGitURI(uri string) (gu *GitURI, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		err = fmt.Errorf("Error parsing uri: '%v'", err)
+		return
+	}
+	switch u.Scheme {
+	case "file", "git", "http", "https", "ssh":
+	default:
+		err = fmt.Errorf("Unsupported git uri scheme '%v'", u.Scheme)
+		return
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	// Skip the account and the repository, which local paths may not have
+	for i := 1; i < len(parts)-1; i++ {
+		if (parts[i] != GitBlob) && (parts[i] != GitCommit) && (parts[i] != GitTree) {
+			continue
+		}
+		repo := parts[:i]
+		if repo[len(repo)-1] == "-" {
+			repo = repo[:len(repo)-1]
+		}
+		gu = &GitURI{
+			Kind:    parts[i],
+			RefPath: strings.Join(parts[i+1:], "/"),
+		}
+		if u.Scheme == "file" {
+			gu.Repository = filepath.FromSlash("/" + strings.Join(repo, "/"))
+		} else {
+			r := *u
+			r.Path = "/" + strings.Join(repo, "/")
+			r.RawQuery, r.Fragment = "", ""
+			gu.Repository = r.String()
+		}
+		if (gu.Kind == GitCommit) && strings.Contains(gu.RefPath, "/") {
+			gu, err = nil, fmt.Errorf("Commit uri '%v' has a path", uri)
+		}
+		return
+	}
+	err = fmt.Errorf("No blob, tree or commit in git uri '%v'", uri)
+	return
+}
+
+// IsGitURI returns true if the uri is a git uri understood by ParseGitURI
+func IsGitURI(uri string) bool {
+	_, err := ParseGitURI(uri)
+	return err == nil
+}
+
+// Open returns the repository of the uri. Local repositories are opened in
+// place, remote ones are fetched into memory.
+func (gu *GitURI) Open() (repo *git.Repository, err error) {
+	if filepath.IsAbs(gu.Repository) {
+		if repo, err = git.PlainOpen(gu.Repository); err != nil {
+			err = fmt.Errorf("Could not open repository '%v': '%v'", gu.Repository, err)
+		}
+		return
+	}
+	if repo, err = git.Init(memory.NewStorage(), nil); err != nil {
+		return
+	}
+	if _, err = repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{gu.Repository}}); err != nil {
+		return
+	}
+	err = fetchRemote(repo, gu.Repository)
+	return
+}
+
+// fetchRemote fetches every branch and tag of the origin remote
+func fetchRemote(repo *git.Repository, remoteurl string) (err error) {
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return
+	}
+	err = remote.Fetch(&git.FetchOptions{RefSpecs: fetchRefSpecs, Tags: git.NoTags})
+	if err == git.NoErrAlreadyUpToDate {
+		err = nil
+	} else if err != nil {
+		err = fmt.Errorf("Could not fetch repository '%v': '%v'", remoteurl, err)
+	}
+	return
+}
+
+// Resolve splits the ref from the path and returns the commit the ref points to,
+// along with the path in it. Refs containing slashes are tried from the longest.
+// Branches of fetched repositories are read from their remote-tracking branch.
+func (gu *GitURI) Resolve(repo *git.Repository) (commit *object.Commit, sourcepath string, err error) {
+	parts := strings.Split(gu.RefPath, "/")
+	for i := len(parts); i > 0; i-- {
+		hash, retErr := gu.resolveRevision(repo, strings.Join(parts[:i], "/"))
+		if retErr != nil {
+			continue
+		}
+		if commit, err = repo.CommitObject(hash); err != nil {
+			return
+		}
+		sourcepath = strings.Join(parts[i:], "/")
+		return
+	}
+	err = fmt.Errorf("No branch, tag or commit found for '%v' in '%v'", gu.RefPath, gu.Repository)
+	return
+}
+
+// resolveRevision resolves a ref of the uri, preferring the remote-tracking
+// branch of a fetched repository over a local branch of the same name
+func (gu *GitURI) resolveRevision(repo *git.Repository, revision string) (hash plumbing.Hash, err error) {
+	if !filepath.IsAbs(gu.Repository) {
+		if hash, err = ResolveRevision(repo, git.DefaultRemoteName+"/"+revision); err == nil {
+			return
+		}
+	}
+	return ResolveRevision(repo, revision)
+}
+
+// ResolveRevision takes a branch, a tag or a full commit hash and returns the
+// hash of the commit it points to. Packed refs and annotated tags are resolved.
+func ResolveRevision(repo *git.Repository, revision string) (hash plumbing.Hash, err error) {
+	if revision == "" {
+		err = errors.New("Empty revision")
+		return
+	}
+	h, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		err = fmt.Errorf("Could not resolve revision '%v': '%v'", revision, err)
+		return
+	}
+	hash = *h
+	return
+}
+
+// ReadGitURI returns the file a blob uri points to, or the 'ethpm.json' manifest
+// in the directory of a tree uri or at the root of a commit uri
+func ReadGitURI(uri string) (content []byte, err error) {
+	return Repositories{}.ReadGitURI(uri)
+}
+
+// Repositories holds the repositories opened for git uris, keyed by
+// GitURI.Repository, so that reading several uris of the same repository opens,
+// and for remote repositories fetches, it only once
+type Repositories map[string]*git.Repository
+
+// Open returns the repository of the uri, opening it on first use
+func (rs Repositories) Open(gu *GitURI) (repo *git.Repository, err error) {
+	if repo, ok := rs[gu.Repository]; ok {
+		return repo, nil
+	}
+	if repo, err = gu.Open(); err != nil {
+		return
+	}
+	rs[gu.Repository] = repo
+	return
+}
+
+// ReadGitURI reads a git uri as ReadGitURI does, reusing the repositories
+// already opened
+func (rs Repositories) ReadGitURI(uri string) (content []byte, err error) {
+	gu, err := ParseGitURI(uri)
+	if err != nil {
+		return
+	}
+	repo, err := rs.Open(gu)
+	if err != nil {
+		return
+	}
+	commit, p, err := gu.Resolve(repo)
+	if err != nil {
+		return
+	}
+	if gu.Kind != GitBlob {
+		p = path.Join(p, ManifestFile)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return
+	}
+	f, err := tree.File(p)
+	if err != nil {
+		err = fmt.Errorf("Could not find '%v' at commit '%v': '%v'", p, commit.Hash, err)
+		return
+	}
+	r, err := f.Reader()
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// cloneTo returns the repository of the uri cloned into dir, fetching into the
+// existing clone if there is one. A directory which is not empty and is not a
+// clone of the uri is refused. Local repositories are opened in place.
+func (gu *GitURI) cloneTo(dir string) (repo *git.Repository, err error) {
+	if filepath.IsAbs(gu.Repository) {
+		return gu.Open()
+	}
+	if repo, err = git.PlainOpen(dir); err == git.ErrRepositoryNotExists {
+		if entries, retErr := ioutil.ReadDir(dir); (retErr == nil) && (len(entries) > 0) {
+			err = fmt.Errorf("Directory '%v' exists and is not a git repository", dir)
+			return
+		}
+		if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+			return
+		}
+		if repo, err = git.PlainInit(dir, false); err != nil {
+			return
+		}
+		if _, err = repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{gu.Repository}}); err != nil {
+			return
+		}
+	} else if err != nil {
+		return
+	} else if err = gu.checkOrigin(repo, dir); err != nil {
+		return
+	}
+	err = fetchRemote(repo, gu.Repository)
+	return
+}
+
+// checkOrigin returns an error if the origin remote of the clone in dir is not
+// the repository of the uri
+func (gu *GitURI) checkOrigin(repo *git.Repository, dir string) (err error) {
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		err = fmt.Errorf("Directory '%v' is not a clone of '%v': '%v'", dir, gu.Repository, err)
+		return
+	}
+	if urls := remote.Config().URLs; (len(urls) == 0) || (urls[0] != gu.Repository) {
+		err = fmt.Errorf("Directory '%v' is a clone of '%v', not '%v'", dir, strings.Join(urls, ", "), gu.Repository)
+	}
+	return
+}
@@ -0,0 +1,240 @@
+package githubutils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestParseGitURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want GitURI
+	}{
+		{"https://github.com/ethpm/token/commit/0123", GitURI{GitCommit, "https://github.com/ethpm/token", "0123"}},
+		{"https://gitlab.com/ethpm/token/-/tree/v1/pkg", GitURI{GitTree, "https://gitlab.com/ethpm/token", "v1/pkg"}},
+		{"https://git.example.com/a/b/blob/feature/x/Token.sol", GitURI{GitBlob, "https://git.example.com/a/b", "feature/x/Token.sol"}},
+		{"file:///srv/git/token.git/blob/master/Token.sol", GitURI{GitBlob, filepath.FromSlash("/srv/git/token.git"), "master/Token.sol"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseGitURI(tt.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != tt.want {
+			t.Fatalf("Got '%+v', expected '%+v'", *got, tt.want)
+		}
+	}
+	for _, uri := range []string{"ipfs://QmbeVyFLSuEUxiXKwSsEjef6icpdTdA4kGG9BcrJXKNKUW", "https://github.com/ethpm/token", "https://github.com/ethpm/token/commit/0123/ethpm.json"} {
+		if IsGitURI(uri) {
+			t.Fatalf("Got '%v', expected '%v' not to be a git uri", true, uri)
+		}
+	}
+}
+
+func TestReadGitURI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "githubutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "ethpm", Email: "ethpm@example.com", When: time.Unix(1540000000, 0)}
+	commit := func(files map[string]string) plumbing.Hash {
+		for k, v := range files {
+			p := filepath.Join(dir, filepath.FromSlash(k))
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(p, []byte(v), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := wt.Add(k); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h, err := wt.Commit("commit", &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	first := commit(map[string]string{"ethpm.json": `{"version":"1.0.0"}`, "pkg/ethpm.json": `{"version":"sub"}`})
+	if _, err = repo.CreateTag("v1.0.0", first, &git.CreateTagOptions{Tagger: sig, Message: "v1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	second := commit(map[string]string{"ethpm.json": `{"version":"2.0.0"}`, "contracts/Token.sol": "contract Token {}\n"})
+	if err = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature/x", second)); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Storer.PackRefs(); err != nil {
+		t.Fatal(err)
+	}
+	// The .git directory is opened as a bare repository
+	base := "file://" + filepath.ToSlash(filepath.Join(dir, ".git"))
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{base + "/commit/" + first.String(), `{"version":"1.0.0"}`},
+		{base + "/tree/master", `{"version":"2.0.0"}`},
+		{base + "/tree/v1.0.0/pkg", `{"version":"sub"}`},
+		{base + "/blob/feature/x/contracts/Token.sol", "contract Token {}\n"},
+	}
+	for _, tt := range tests {
+		got, err := ReadGitURI(tt.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Fatalf("Got '%v' for '%v', expected '%v'", string(got), tt.uri, tt.want)
+		}
+	}
+	if _, err = ReadGitURI(base + "/blob/missing/Token.sol"); err == nil {
+		t.Fatalf("Got '%v', expected an error for an unknown ref", err)
+	}
+	repos := Repositories{}
+	for _, tt := range tests {
+		if _, err = repos.ReadGitURI(tt.uri); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(repos) != 1 {
+		t.Fatalf("Got '%v', expected '%v' repository opened", len(repos), 1)
+	}
+	// A cached repository is read without fetching its remote
+	repos["https://git.invalid/ethpm/token"] = repo
+	if _, err = repos.ReadGitURI("https://git.invalid/ethpm/token/blob/master/contracts/Token.sol"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetCommit(filepath.Join(dir, ".git"), "feature/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != second.String() {
+		t.Fatalf("Got '%v', expected '%v'", got, second.String())
+	}
+	manifest, err := GetETHPMManifest(base+"/tree/v1.0.0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest != `{"version":"1.0.0"}` {
+		t.Fatalf("Got '%v', expected '%v'", manifest, `{"version":"1.0.0"}`)
+	}
+}
+
+func TestCloneTo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "githubutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sig := &object.Signature{Name: "ethpm", Email: "ethpm@example.com", When: time.Unix(1540000000, 0)}
+	commit := func(repodir string, content string) plumbing.Hash {
+		repo, err := git.PlainOpen(repodir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(repodir, ManifestFile), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = wt.Add(ManifestFile); err != nil {
+			t.Fatal(err)
+		}
+		h, err := wt.Commit(content, &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	origin := filepath.Join(dir, "origin")
+	if _, err = git.PlainInit(origin, false); err != nil {
+		t.Fatal(err)
+	}
+	commit(origin, `{"version":"1.0.0"}`)
+	gu := &GitURI{Kind: GitTree, Repository: "file://" + filepath.ToSlash(origin), RefPath: "master"}
+
+	clone := filepath.Join(dir, "clone")
+	repo, err := gu.cloneTo(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _, err := gu.Resolve(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = checkClean(wt, clone); err != nil {
+		t.Fatal(err)
+	}
+	if err = wt.Checkout(&git.CheckoutOptions{Hash: c.Hash}); err != nil {
+		t.Fatal(err)
+	}
+	if err = wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master"), Create: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Local commits survive fetching into the clone, the uri resolves to the remote
+	local := commit(clone, `{"version":"local"}`)
+	remote := commit(origin, `{"version":"2.0.0"}`)
+	if repo, err = gu.cloneTo(clone); err != nil {
+		t.Fatal(err)
+	}
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("master"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Hash() != local {
+		t.Fatalf("Got '%v', expected the local branch to stay at '%v'", ref.Hash(), local)
+	}
+	if c, _, err = gu.Resolve(repo); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hash != remote {
+		t.Fatalf("Got '%v', expected '%v'", c.Hash, remote)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(clone, ManifestFile), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = checkClean(wt, clone); err == nil {
+		t.Fatalf("Got '%v', expected an error for uncommitted changes", err)
+	}
+	other := &GitURI{Kind: GitTree, Repository: "https://github.com/ethpm/other", RefPath: "master"}
+	if _, err = other.cloneTo(clone); err == nil {
+		t.Fatalf("Got '%v', expected an error for a clone of another repository", err)
+	}
+	if _, err = gu.cloneTo(origin); err == nil {
+		t.Fatalf("Got '%v', expected an error for a directory which is not a clone", err)
+	}
+	notrepo := filepath.Join(dir, "notrepo")
+	if err = os.MkdirAll(notrepo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(notrepo, "notes.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = gu.cloneTo(notrepo); err == nil {
+		t.Fatalf("Got '%v', expected an error for a directory which is not a repository", err)
+	}
+}
@@ -20,22 +20,27 @@ package librarylink
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/ethpm/ethpm-go/pkg/ethregexlib"
 )
 
-// LinkReference A defined location in some bytecode which requires linking
+// LinkReference A defined location in some bytecode which requires linking. The
+// source of the library is not part of the v2 spec and is written as "x-source".
 type LinkReference struct {
 	Offsets []int  `json:"offsets"`
 	Length  int    `json:"length"`
 	Name    string `json:"name,omitempty"`
+	Source  string `json:"x-source,omitempty"`
 }
 
-// Build Takes a name for the linked contract, as well as the offset array from
-// the compiler statndard json output link references key for this contract, and
-// builds the LinkReference struct
-func (l *LinkReference) Build(name string, offsets []map[string]int) {
+// Build Takes the source path and name of the linked contract, as well as the
+// offset array from the compiler statndard json output link references key for
+// this contract, and builds the LinkReference struct
+func (l *LinkReference) Build(source string, name string, offsets []map[string]int) {
 	l.Name = name
+	l.Source = source
 	if len(offsets) > 0 {
 		l.Length = offsets[0]["length"]
 		l.Offsets = make([]int, len(offsets))
@@ -46,6 +51,48 @@ func (l *LinkReference) Build(name string, offsets []map[string]int) {
 	return
 }
 
+// FullyQualifiedName returns the "source:Name" identity of the linked contract,
+// or only its name if the source is not known
+func (l *LinkReference) FullyQualifiedName() string {
+	if l.Source == "" {
+		return l.Name
+	}
+	return l.Source + ":" + l.Name
+}
+
+// CheckAmbiguousNames takes link references, such as those of every contract
+// type of a package, and returns an error if libraries with the same name are
+// linked from different source paths, as they cannot be told apart by name
+func CheckAmbiguousNames(refs []*LinkReference) (err error) {
+	sources := make(map[string]map[string]bool)
+	for _, l := range refs {
+		if (l.Name == "") || (l.Source == "") {
+			continue
+		}
+		if sources[l.Name] == nil {
+			sources[l.Name] = make(map[string]bool)
+		}
+		sources[l.Name][l.Source] = true
+	}
+	var names []string
+	for k, v := range sources {
+		if len(v) > 1 {
+			names = append(names, k)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+	var paths []string
+	for k := range sources[names[0]] {
+		paths = append(paths, k+":"+names[0])
+	}
+	sort.Strings(paths)
+	err = fmt.Errorf("Library name '%v' is ambiguous, it is linked as '%v'", names[0], strings.Join(paths, "', '"))
+	return
+}
+
 // Validate ensures the LinkReference struct conforms to the standard found
 // here https://ethpm.github.io/ethpm-spec/package-spec.html#the-link-reference-object
 func (l *LinkReference) Validate() (err error) {
@@ -26,3 +26,35 @@ func TestLRValidate(t *testing.T) {
 		t.Fatalf("Got '%v', expected <nil>", got)
 	}
 }
+
+func TestLRFullyQualifiedName(t *testing.T) {
+	l := LinkReference{}
+	l.Build("contracts/Math.sol", "SafeMath", []map[string]int{{"length": 20, "start": 4}})
+	if got, want := l.FullyQualifiedName(), "contracts/Math.sol:SafeMath"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if (l.Length != 20) || (len(l.Offsets) != 1) || (l.Offsets[0] != 4) {
+		t.Fatalf("Got '%+v', expected a length of 20 at offset 4", l)
+	}
+	l.Source = ""
+	if got, want := l.FullyQualifiedName(), "SafeMath"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestCheckAmbiguousNames(t *testing.T) {
+	refs := []*LinkReference{
+		{Name: "SafeMath", Source: "contracts/Math.sol"},
+		{Name: "SafeMath", Source: "contracts/Math.sol"},
+		{Name: "Owned"},
+	}
+	if err := CheckAmbiguousNames(refs); err != nil {
+		t.Fatalf("Got '%v', expected <nil>", err)
+	}
+	refs = append(refs, &LinkReference{Name: "SafeMath", Source: "./node_modules/math/Math.sol"})
+	want := errors.New("Library name 'SafeMath' is ambiguous, it is linked as " +
+		"'./node_modules/math/Math.sol:SafeMath', 'contracts/Math.sol:SafeMath'")
+	if got := CheckAmbiguousNames(refs); (got == nil) || (got.Error() != want.Error()) {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
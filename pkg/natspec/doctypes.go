@@ -17,64 +17,292 @@ with Ethereum compiler natspec output
 */
 package natspec
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
 
-// Method defines a method object in the doc json
+// customPrefix starts the keys of custom:* tags in the doc json
+const customPrefix = "custom:"
+
+// Method defines a method, event, error or state variable object in the doc
+// json. Custom tags are kept without their "custom:" prefix.
 type Method struct {
+	Custom  map[string]string `json:"-"`
 	Details string            `json:"details,omitempty"`
+	Notice  string            `json:"notice,omitempty"`
 	Params  map[string]string `json:"params,omitempty"`
 	Return  string            `json:"return,omitempty"`
+	Returns map[string]string `json:"returns,omitempty"`
 }
 
 // DevDoc The output of an ethereum compiler's natspec developer documentation
 type DevDoc struct {
-	Author       string              `json:"author,omitempty"`
-	Construction []map[string]string `json:"construction,omitempty"`
-	Invariants   []map[string]string `json:"invariants,omitempty"`
-	Methods      map[string]*Method  `json:"methods,omitempty"`
-	Title        string              `json:"title,omitempty"`
+	Author         string               `json:"author,omitempty"`
+	Construction   []map[string]string  `json:"construction,omitempty"`
+	Custom         map[string]string    `json:"-"`
+	Details        string               `json:"details,omitempty"`
+	Errors         map[string][]*Method `json:"errors,omitempty"`
+	Events         map[string]*Method   `json:"events,omitempty"`
+	Invariants     []map[string]string  `json:"invariants,omitempty"`
+	Kind           string               `json:"kind,omitempty"`
+	Methods        map[string]*Method   `json:"methods,omitempty"`
+	StateVariables map[string]*Method   `json:"stateVariables,omitempty"`
+	Title          string               `json:"title,omitempty"`
+	Version        int                  `json:"version,omitempty"`
 }
 
 // UserDoc The output of am ethereum compiler's natspec user documentation
 type UserDoc struct {
-	Construction    []map[string]string `json:"construction,omitempty"`
-	Invariants      []map[string]string `json:"invariants,omitempty"`
-	Language        string              `json:"language,omitempty"`
-	LanguageVersion string              `json:"languageVersion,omitempty"`
-	Methods         map[string]*Method  `json:"methods,omitempty"`
-	Source          string              `json:"source,omitempty"`
+	Construction    []map[string]string  `json:"construction,omitempty"`
+	Errors          map[string][]*Method `json:"errors,omitempty"`
+	Events          map[string]*Method   `json:"events,omitempty"`
+	Invariants      []map[string]string  `json:"invariants,omitempty"`
+	Kind            string               `json:"kind,omitempty"`
+	Language        string               `json:"language,omitempty"`
+	LanguageVersion string               `json:"languageVersion,omitempty"`
+	Methods         map[string]*Method   `json:"methods,omitempty"`
+	Notice          string               `json:"notice,omitempty"`
+	Source          string               `json:"source,omitempty"`
+	Version         int                  `json:"version,omitempty"`
 }
 
 // DocUnion The union of devdoc and userdoc
 type DocUnion struct {
-	Author          string              `json:"author,omitempty"`
-	Construction    []map[string]string `json:"construction,omitempty"`
-	Invariants      []map[string]string `json:"invariants,omitempty"`
-	Language        string              `json:"language,omitempty"`
-	LanguageVersion string              `json:"languageVersion,omitempty"`
-	Methods         map[string]*Method  `json:"methods,omitempty"`
-	Source          string              `json:"source,omitempty"`
-	Title           string              `json:"title,omitempty"`
+	Author          string               `json:"author,omitempty"`
+	Construction    []map[string]string  `json:"construction,omitempty"`
+	Custom          map[string]string    `json:"-"`
+	Details         string               `json:"details,omitempty"`
+	Errors          map[string][]*Method `json:"errors,omitempty"`
+	Events          map[string]*Method   `json:"events,omitempty"`
+	Invariants      []map[string]string  `json:"invariants,omitempty"`
+	Language        string               `json:"language,omitempty"`
+	LanguageVersion string               `json:"languageVersion,omitempty"`
+	Methods         map[string]*Method   `json:"methods,omitempty"`
+	Notice          string               `json:"notice,omitempty"`
+	Source          string               `json:"source,omitempty"`
+	StateVariables  map[string]*Method   `json:"stateVariables,omitempty"`
+	Title           string               `json:"title,omitempty"`
+	Version         int                  `json:"version,omitempty"`
 }
 
 // CreateUnion takes a DevDoc and UserDoc struct and combines them into a
-// DocUnion struct
+// DocUnion struct. Methods, events and errors documented in both keep the
+// developer and the user text.
 func (du *DocUnion) CreateUnion(dd *DevDoc, ud *UserDoc) {
 	if ud != nil {
+		du.Construction = append(du.Construction, ud.Construction...)
+		du.Errors = mergeErrors(du.Errors, ud.Errors)
+		du.Events = mergeMethods(du.Events, ud.Events)
+		du.Invariants = append(du.Invariants, ud.Invariants...)
 		du.Language = ud.Language
 		du.LanguageVersion = ud.LanguageVersion
+		du.Methods = mergeMethods(du.Methods, ud.Methods)
+		du.Notice = ud.Notice
 		du.Source = ud.Source
+		du.Version = ud.Version
 	} else {
 		fmt.Println("User Docs not included in output.")
 	}
 	if dd != nil {
 		du.Author = dd.Author
-		du.Construction = dd.Construction
-		du.Invariants = dd.Invariants
-		du.Methods = dd.Methods
+		du.Construction = append(du.Construction, dd.Construction...)
+		du.Custom = mergeStrings(du.Custom, dd.Custom)
+		du.Details = dd.Details
+		du.Errors = mergeErrors(du.Errors, dd.Errors)
+		du.Events = mergeMethods(du.Events, dd.Events)
+		du.Invariants = append(du.Invariants, dd.Invariants...)
+		du.Methods = mergeMethods(du.Methods, dd.Methods)
+		du.StateVariables = mergeMethods(du.StateVariables, dd.StateVariables)
 		du.Title = dd.Title
+		if dd.Version != 0 {
+			du.Version = dd.Version
+		}
 	} else {
 		fmt.Println("Developer Docs not included in output.")
 	}
 	return
 }
+
+// Merge fills the fields of the method which are empty with those of another,
+// so the user notice and the developer details of a method end up together
+func (m *Method) Merge(o *Method) {
+	if o == nil {
+		return
+	}
+	m.Custom = mergeStrings(m.Custom, o.Custom)
+	if m.Details == "" {
+		m.Details = o.Details
+	}
+	if m.Notice == "" {
+		m.Notice = o.Notice
+	}
+	m.Params = mergeStrings(m.Params, o.Params)
+	if m.Return == "" {
+		m.Return = o.Return
+	}
+	m.Returns = mergeStrings(m.Returns, o.Returns)
+	return
+}
+
+// UnmarshalJSON reads the method along with its custom:* tags
+func (m *Method) UnmarshalJSON(data []byte) (err error) {
+	type method Method
+	if err = json.Unmarshal(data, (*method)(m)); err != nil {
+		return
+	}
+	m.Custom, err = readCustom(data)
+	return
+}
+
+// MarshalJSON writes the method along with its custom:* tags
+func (m *Method) MarshalJSON() ([]byte, error) {
+	type method Method
+	return writeCustom((*method)(m), m.Custom)
+}
+
+// UnmarshalJSON reads the devdoc along with its custom:* tags
+func (dd *DevDoc) UnmarshalJSON(data []byte) (err error) {
+	type devDoc DevDoc
+	if err = json.Unmarshal(data, (*devDoc)(dd)); err != nil {
+		return
+	}
+	dd.Custom, err = readCustom(data)
+	return
+}
+
+// MarshalJSON writes the devdoc along with its custom:* tags
+func (dd *DevDoc) MarshalJSON() ([]byte, error) {
+	type devDoc DevDoc
+	return writeCustom((*devDoc)(dd), dd.Custom)
+}
+
+// UnmarshalJSON reads the union along with its custom:* tags
+func (du *DocUnion) UnmarshalJSON(data []byte) (err error) {
+	type docUnion DocUnion
+	if err = json.Unmarshal(data, (*docUnion)(du)); err != nil {
+		return
+	}
+	du.Custom, err = readCustom(data)
+	return
+}
+
+// MarshalJSON writes the union along with its custom:* tags
+func (du *DocUnion) MarshalJSON() ([]byte, error) {
+	type docUnion DocUnion
+	return writeCustom((*docUnion)(du), du.Custom)
+}
+
+// CustomTags returns the names of the custom tags, sorted
+func CustomTags(custom map[string]string) (tags []string) {
+	for k := range custom {
+		tags = append(tags, k)
+	}
+	sort.Strings(tags)
+	return
+}
+
+// readCustom returns the custom:* tags of a json object without their prefix
+func readCustom(data []byte) (custom map[string]string, err error) {
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	for k, v := range raw {
+		if !strings.HasPrefix(k, customPrefix) {
+			continue
+		}
+		var s string
+		if err = json.Unmarshal(v, &s); err != nil {
+			err = fmt.Errorf("Invalid tag '%v': '%v'", k, err)
+			return
+		}
+		if custom == nil {
+			custom = make(map[string]string)
+		}
+		custom[strings.TrimPrefix(k, customPrefix)] = s
+	}
+	return
+}
+
+// writeCustom marshals v and adds the custom tags to the resulting json object
+func writeCustom(v interface{}, custom map[string]string) (data []byte, err error) {
+	if data, err = json.Marshal(v); (err != nil) || (len(custom) == 0) {
+		return
+	}
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	for k, s := range custom {
+		b, _ := json.Marshal(s)
+		raw[customPrefix+k] = b
+	}
+	return json.Marshal(raw)
+}
+
+// mergeStrings returns a copy of a with the entries of b it does not have
+func mergeStrings(a map[string]string, b map[string]string) (merged map[string]string) {
+	if (len(a) == 0) && (len(b) == 0) {
+		return
+	}
+	merged = make(map[string]string)
+	for k, v := range b {
+		merged[k] = v
+	}
+	for k, v := range a {
+		merged[k] = v
+	}
+	return
+}
+
+// mergeMethods returns a copy of a with the methods of b merged in
+func mergeMethods(a map[string]*Method, b map[string]*Method) (merged map[string]*Method) {
+	if (len(a) == 0) && (len(b) == 0) {
+		return
+	}
+	merged = make(map[string]*Method)
+	for k, v := range a {
+		merged[k] = copyMethod(v)
+	}
+	for k, v := range b {
+		if m, ok := merged[k]; ok {
+			m.Merge(v)
+		} else {
+			merged[k] = copyMethod(v)
+		}
+	}
+	return
+}
+
+// mergeErrors returns a copy of a with the errors of b merged in. Overloaded
+// errors are listed in the same order by both docs and merged by position.
+func mergeErrors(a map[string][]*Method, b map[string][]*Method) (merged map[string][]*Method) {
+	if (len(a) == 0) && (len(b) == 0) {
+		return
+	}
+	merged = make(map[string][]*Method)
+	for k, v := range a {
+		for _, m := range v {
+			merged[k] = append(merged[k], copyMethod(m))
+		}
+	}
+	for k, v := range b {
+		for i, m := range v {
+			if i < len(merged[k]) {
+				merged[k][i].Merge(m)
+			} else {
+				merged[k] = append(merged[k], copyMethod(m))
+			}
+		}
+	}
+	return
+}
+
+func copyMethod(m *Method) (c *Method) {
+	c = &Method{}
+	c.Merge(m)
+	return
+}
@@ -0,0 +1,91 @@
+package natspec
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testDevDoc = `{"author":"Larry A. Gardner","custom:security":"audited","details":"All function calls are currently implemented",` +
+	`"errors":{"InsufficientBalance(uint256,uint256)":[{"params":{"available":"balance available."}}]},` +
+	`"events":{"Transfer(address,address,uint256)":{"details":"Emitted on every transfer"}},"kind":"dev",` +
+	`"methods":{"age(uint256)":{"custom:since":"1.0.0","details":"The Alexandr N. Tetearing algorithm could increase precision",` +
+	`"params":{"rings":"The number of rings from dendrochronological sample"},"returns":{"_0":"Age in years, rounded up"}}},` +
+	`"stateVariables":{"owner":{"details":"The owner of the tree"}},"title":"A simulator for trees","version":1}`
+
+const testUserDoc = `{"errors":{"InsufficientBalance(uint256,uint256)":[{"notice":"Insufficient balance for transfer."}]},` +
+	`"events":{"Transfer(address,address,uint256)":{"notice":"Tokens moved"}},"kind":"user",` +
+	`"methods":{"age(uint256)":{"notice":"Calculate tree age in years, rounded up"},"leaves()":{"notice":"Returns the amount of leaves"}},` +
+	`"notice":"You can use this contract for only the most basic simulation","version":1}`
+
+func TestCreateUnion(t *testing.T) {
+	dd := &DevDoc{}
+	if err := json.Unmarshal([]byte(testDevDoc), dd); err != nil {
+		t.Fatal(err)
+	}
+	ud := &UserDoc{}
+	if err := json.Unmarshal([]byte(testUserDoc), ud); err != nil {
+		t.Fatal(err)
+	}
+	du := &DocUnion{}
+	du.CreateUnion(dd, ud)
+
+	if du.Notice != ud.Notice {
+		t.Fatalf("Got '%v', expected '%v'", du.Notice, ud.Notice)
+	}
+	if du.Custom["security"] != "audited" {
+		t.Fatalf("Got '%v', expected '%v'", du.Custom["security"], "audited")
+	}
+	m := du.Methods["age(uint256)"]
+	if m.Notice != "Calculate tree age in years, rounded up" {
+		t.Fatalf("Got '%v', expected '%v'", m.Notice, "Calculate tree age in years, rounded up")
+	}
+	if m.Details != "The Alexandr N. Tetearing algorithm could increase precision" {
+		t.Fatalf("Got '%v', expected '%v'", m.Details, "The Alexandr N. Tetearing algorithm could increase precision")
+	}
+	if (m.Returns["_0"] != "Age in years, rounded up") || (m.Custom["since"] != "1.0.0") {
+		t.Fatalf("Got '%+v', expected returns and custom tags", m)
+	}
+	if du.Methods["leaves()"].Notice != "Returns the amount of leaves" {
+		t.Fatalf("Got '%v', expected '%v'", du.Methods["leaves()"].Notice, "Returns the amount of leaves")
+	}
+	e := du.Events["Transfer(address,address,uint256)"]
+	if (e.Notice != "Tokens moved") || (e.Details != "Emitted on every transfer") {
+		t.Fatalf("Got '%+v', expected the event notice and details", e)
+	}
+	errs := du.Errors["InsufficientBalance(uint256,uint256)"]
+	if (len(errs) != 1) || (errs[0].Notice != "Insufficient balance for transfer.") || (errs[0].Params["available"] != "balance available.") {
+		t.Fatalf("Got '%+v', expected one merged error", errs)
+	}
+	if du.StateVariables["owner"].Details != "The owner of the tree" {
+		t.Fatalf("Got '%v', expected '%v'", du.StateVariables["owner"].Details, "The owner of the tree")
+	}
+	if du.Version != 1 {
+		t.Fatalf("Got '%v', expected '%v'", du.Version, 1)
+	}
+	if dd.Methods["age(uint256)"].Notice != "" {
+		t.Fatalf("Got '%v', expected the devdoc to be left unchanged", dd.Methods["age(uint256)"].Notice)
+	}
+}
+
+func TestDocUnionJSON(t *testing.T) {
+	dd := &DevDoc{}
+	if err := json.Unmarshal([]byte(testDevDoc), dd); err != nil {
+		t.Fatal(err)
+	}
+	du := &DocUnion{}
+	du.CreateUnion(dd, nil)
+	b, err := json.Marshal(du)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &DocUnion{}
+	if err = json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Custom["security"] != "audited" {
+		t.Fatalf("Got '%v', expected '%v'", got.Custom["security"], "audited")
+	}
+	if got.Methods["age(uint256)"].Custom["since"] != "1.0.0" {
+		t.Fatalf("Got '%v', expected '%v'", got.Methods["age(uint256)"].Custom["since"], "1.0.0")
+	}
+}
@@ -17,27 +17,23 @@ installed solc compiler.
 */
 package solcutils
 
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"io/ioutil"
-	"os"
-	"os/exec"
-)
-
 // SolcOptimizer represents the optimizer settings in a standard json input
 // https://solidity.readthedocs.io/en/v0.4.24/using-the-compiler.html#input-description
 type SolcOptimizer struct {
-	Enabled bool `json:"enabled,omitempty"`
-	Runs    int  `json:"runs,omitempty"`
+	Enabled bool                  `json:"enabled,omitempty"`
+	Runs    int                   `json:"runs,omitempty"`
+	Details *SolcOptimizerDetails `json:"details,omitempty"`
 }
 
 // SolcSettings represents the settings object in a standard json input
 type SolcSettings struct {
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	Libraries       map[string]map[string]string   `json:"libraries,omitempty"`
+	Metadata        *SolcMetadataSettings          `json:"metadata,omitempty"`
 	Optimizer       *SolcOptimizer                 `json:"optimizer,omitempty"`
 	OutputSelection map[string]map[string][]string `json:"outputSelection,omitempty"`
 	Remappings      []string                       `json:"remappings,omitempty"`
+	ViaIR           bool                           `json:"viaIR,omitempty"`
 }
 
 // StandardInput represents the standard json input object
@@ -48,7 +44,8 @@ type StandardInput struct {
 }
 
 // CompileFileAsString takes all of the information requested and returns the
-// standard input and standard output json objects as strings
+// standard input and standard output json objects as strings. The default output
+// selection from NewSolcSettings is requested.
 func CompileFileAsString(compiler string,
 	projectdir string,
 	contractname string,
@@ -59,26 +56,22 @@ func CompileFileAsString(compiler string,
 	optimize bool,
 	runs int,
 ) (stdinjson string, stdoutjson string, err error) {
-	so := SolcOptimizer{
-		optimize,
-		runs,
-	}
-	selections := []string{"evm.bytecode", "evm.deployedBytecode"}
-	opsinner := map[string][]string{
-		"*": selections,
-	}
-	ops := map[string]map[string][]string{
-		"*": opsinner,
-	}
-	remappings := make([]string, len(dependencies))
-	for i, v := range dependencies {
-		remappings[i] = v + "/=./" + v + "/"
-	}
-	ss := SolcSettings{
-		&so,
-		ops,
-		remappings,
-	}
+	ss := NewSolcSettings(optimize, runs)
+	ss.Remappings = DependencyRemappings(dependencies)
+	return CompileFileWithSettings(compiler, projectdir, contractname, inline, filepath, fileasstring, ss)
+}
+
+// CompileFileWithSettings takes the compiler, project directory and source
+// information used by CompileFileAsString along with the full compiler settings,
+// then returns the standard input and standard output json objects as strings
+func CompileFileWithSettings(compiler string,
+	projectdir string,
+	contractname string,
+	inline bool,
+	filepath string,
+	fileasstring string,
+	settings *SolcSettings,
+) (stdinjson string, stdoutjson string, err error) {
 	sourcesinner := make(map[string]string)
 	if inline {
 		sourcesinner["content"] = fileasstring
@@ -91,61 +84,7 @@ func CompileFileAsString(compiler string,
 	si := StandardInput{
 		"Solidity",
 		sources,
-		&ss,
-	}
-
-	tjson, _ := json.Marshal(si)
-	tmpfile, err := ioutil.TempFile("", "tsol")
-	defer os.Remove(tmpfile.Name())
-	if err != nil {
-		err = fmt.Errorf("Error creating temp json: '%v'", err)
-		return
-	}
-	if _, err = tmpfile.Write(tjson); err != nil {
-		err = fmt.Errorf("Error writing temp json: '%v'", err)
-		return
-	}
-	if err = tmpfile.Close(); err != nil {
-		err = fmt.Errorf("Error closing temp json: '%v'", err)
-		return
-	}
-	execlocation, err := exec.LookPath(compiler)
-	if err != nil {
-		err = fmt.Errorf("Error getting solc bin location: '%v'", err)
-		return
-	}
-
-	var wd string
-	if projectdir == "" {
-		wd, err = os.Getwd()
-		if err != nil {
-			err = fmt.Errorf("Error getting working directory: '%v'", err)
-			return
-		}
-	} else {
-		wd = projectdir
-	}
-
-	execCmd := exec.Command(execlocation, "--allow-paths", wd, "--standard-json")
-	f, err := os.Open(tmpfile.Name())
-	i, err := f.Stat()
-	b := make([]byte, i.Size())
-	f.Read(b)
-	stdinjson = string(b)
-	f.Seek(0, 0)
-	if err != nil {
-		err = fmt.Errorf("Error reading temp json: '%v'", err)
-		return
-	}
-	stdin, _ := execCmd.StdinPipe()
-	io.Copy(stdin, f)
-	stdin.Close()
-	f.Close()
-	execOut, err := execCmd.Output()
-	if err != nil {
-		err = fmt.Errorf("Error calling exec command: '%v'", err)
-		return
+		settings,
 	}
-	stdoutjson = string(execOut)
-	return
+	return CompileStandardInput(compiler, projectdir, &si)
 }
@@ -0,0 +1,225 @@
+package solcutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DependencyDir is the directory, relative to the project directory, where the
+// build dependencies of a package are installed
+const DependencyDir = "ethpm-dependencies"
+
+var (
+	importRegex       = regexp.MustCompile(`(?m)\bimport\s+(?:[^'";]*?\s+from\s+)?["']([^"']+)["']`)
+	blockCommentRegex = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	lineCommentRegex  = regexp.MustCompile(`(?m)//.*$`)
+)
+
+// ParseImports takes the content of a solidity source and returns the paths of
+// every import statement in the order they appear. Commented out imports are ignored.
+func ParseImports(source string) (imports []string) {
+	source = blockCommentRegex.ReplaceAllString(source, "")
+	source = lineCommentRegex.ReplaceAllString(source, "")
+	for _, m := range importRegex.FindAllStringSubmatch(source, -1) {
+		imports = append(imports, m[1])
+	}
+	return
+}
+
+// SourceUnitName takes a source key as it appears in a package manifest, such as
+// "./contracts/Token.sol", and returns the name used for it in the standard json
+// input, such as "contracts/Token.sol"
+func SourceUnitName(key string) string {
+	return path.Clean(strings.TrimPrefix(filepath.ToSlash(key), "./"))
+}
+
+// DependencyRemappings takes the names of the build dependencies of a package and
+// returns the remappings which point each of them at its install location
+func DependencyRemappings(dependencies []string) (remappings []string) {
+	remappings = make([]string, len(dependencies))
+	for i, v := range dependencies {
+		remappings[i] = v + "/=" + DependencyDir + "/" + v + "/"
+	}
+	sort.Strings(remappings)
+	return
+}
+
+// ResolveImport takes the source unit name of the importing file, the import path
+// and the remappings in use, then returns the source unit name of the imported file
+func ResolveImport(importer string, importpath string, remappings []string) string {
+	if strings.HasPrefix(importpath, "./") || strings.HasPrefix(importpath, "../") {
+		return path.Join(path.Dir(importer), importpath)
+	}
+	longest := ""
+	target := ""
+	for _, r := range remappings {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		prefix := parts[0]
+		if i := strings.Index(prefix, ":"); i >= 0 {
+			prefix = prefix[i+1:]
+		}
+		if strings.HasPrefix(importpath, prefix) && (len(prefix) > len(longest)) {
+			longest = prefix
+			target = parts[1]
+		}
+	}
+	if longest != "" {
+		importpath = target + strings.TrimPrefix(importpath, longest)
+	}
+	return path.Clean(importpath)
+}
+
+// CollectSources takes the project directory, a mapping of source unit names to
+// the content of sources already held in memory, and the remappings in use. It
+// follows the imports of every source, reading any file which is not held in
+// memory from the project directory, and returns the content of the complete
+// transitive set of sources keyed by source unit name.
+func CollectSources(projectdir string, sources map[string]string, remappings []string) (collected map[string]string, err error) {
+	collected = make(map[string]string)
+	pending := make([]string, 0, len(sources))
+	for k, v := range sources {
+		name := SourceUnitName(k)
+		collected[name] = v
+		pending = append(pending, name)
+	}
+	sort.Strings(pending)
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, imp := range ParseImports(collected[current]) {
+			name := ResolveImport(current, imp, remappings)
+			if _, ok := collected[name]; ok {
+				continue
+			}
+			b, retErr := ioutil.ReadFile(filepath.Join(projectdir, filepath.FromSlash(name)))
+			if retErr != nil {
+				err = fmt.Errorf("Could not resolve import '%v' in '%v': '%v'", imp, current, retErr)
+				return
+			}
+			collected[name] = string(b)
+			pending = append(pending, name)
+		}
+	}
+	return
+}
+
+// BuildProjectInput takes the project directory, a mapping of source keys to
+// source content, the names of the installed build dependencies, and the compiler
+// settings, then returns a standard json input containing every source needed
+// to compile the project. The dependency remappings missing from the settings
+// are added to a copy of them, the given settings are left unchanged.
+func BuildProjectInput(projectdir string, sources map[string]string, dependencies []string, settings *SolcSettings) (si *StandardInput, err error) {
+	if settings == nil {
+		settings = NewSolcSettings(false, 0)
+	}
+	copied := *settings
+	settings = &copied
+	settings.Remappings = append([]string{}, copied.Remappings...)
+	for _, r := range DependencyRemappings(dependencies) {
+		found := false
+		for _, e := range settings.Remappings {
+			if e == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			settings.Remappings = append(settings.Remappings, r)
+		}
+	}
+	collected, err := CollectSources(projectdir, sources, settings.Remappings)
+	if err != nil {
+		err = fmt.Errorf("Error collecting sources: '%v'", err)
+		return
+	}
+	si = &StandardInput{
+		Language: "Solidity",
+		Sources:  make(map[string]map[string]string),
+		Settings: settings,
+	}
+	for k, v := range collected {
+		si.Sources[k] = map[string]string{"content": v}
+	}
+	return
+}
+
+// CompileProject takes the name of the installed compiler, the project directory,
+// a mapping of source keys to source content, the names of the installed build
+// dependencies and the compiler settings, which can be nil and NewSolcSettings
+// without the optimizer will be used. It compiles every source along with the
+// files they import and returns the standard input and standard output json
+// objects as strings.
+func CompileProject(compiler string,
+	projectdir string,
+	sources map[string]string,
+	dependencies []string,
+	settings *SolcSettings,
+) (stdinjson string, stdoutjson string, err error) {
+	if projectdir == "" {
+		if projectdir, err = os.Getwd(); err != nil {
+			err = fmt.Errorf("Error getting working directory: '%v'", err)
+			return
+		}
+	}
+	if settings == nil {
+		settings = NewSolcSettings(false, 0)
+	}
+	si, err := BuildProjectInput(projectdir, sources, dependencies, settings)
+	if err != nil {
+		return
+	}
+	return CompileStandardInput(compiler, projectdir, si)
+}
+
+// CompileStandardInput takes the name of the installed compiler, the directory
+// the compiler is allowed to read from, and a standard json input object. It runs
+// the compiler and returns the standard input and standard output json objects
+// as strings.
+func CompileStandardInput(compiler string, projectdir string, si *StandardInput) (stdinjson string, stdoutjson string, err error) {
+	b, err := json.Marshal(si)
+	if err != nil {
+		err = fmt.Errorf("Error creating standard json input: '%v'", err)
+		return
+	}
+	stdinjson = string(b)
+	stdoutjson, err = RunStandardJSON(compiler, projectdir, stdinjson)
+	return
+}
+
+// RunStandardJSON takes the name or path of the compiler, the directory it is
+// allowed to read from, which can be empty for the working directory, and a
+// standard json input string. It runs solc with "--standard-json" and returns
+// the standard json output as a string.
+func RunStandardJSON(compiler string, projectdir string, stdinjson string) (stdoutjson string, err error) {
+	execlocation, err := exec.LookPath(compiler)
+	if err != nil {
+		err = fmt.Errorf("Error getting solc bin location: '%v'", err)
+		return
+	}
+	if projectdir == "" {
+		if projectdir, err = os.Getwd(); err != nil {
+			err = fmt.Errorf("Error getting working directory: '%v'", err)
+			return
+		}
+	}
+	execCmd := exec.Command(execlocation, "--allow-paths", projectdir, "--standard-json")
+	execCmd.Stdin = strings.NewReader(stdinjson)
+	execOut, err := execCmd.Output()
+	if err != nil {
+		err = fmt.Errorf("Error calling exec command: '%v'", err)
+		return
+	}
+	stdoutjson = string(execOut)
+	return
+}
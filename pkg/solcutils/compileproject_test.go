@@ -0,0 +1,117 @@
+package solcutils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImports(t *testing.T) {
+	s := `pragma solidity ^0.4.24;
+import "./BasicMathLib.sol";
+import './Token.sol' as Token;
+import {Ownable, Pausable as P} from "ethereum-libraries-ownable/contracts/Ownable.sol";
+import * as Lib from "../lib/Lib.sol";
+// import "./Commented.sol";
+/* import "./AlsoCommented.sol"; */
+contract Imports {}
+`
+	got := strings.Join(ParseImports(s), ",")
+	want := "./BasicMathLib.sol,./Token.sol,ethereum-libraries-ownable/contracts/Ownable.sol,../lib/Lib.sol"
+	if got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestResolveImport(t *testing.T) {
+	r := DependencyRemappings([]string{"ethereum-libraries-basic-math"})
+	tests := []struct {
+		importer   string
+		importpath string
+		want       string
+	}{
+		{"contracts/TokenLib.sol", "./BasicMathLib.sol", "contracts/BasicMathLib.sol"},
+		{"contracts/sub/Token.sol", "../TokenLib.sol", "contracts/TokenLib.sol"},
+		{"contracts/TokenLib.sol", "ethereum-libraries-basic-math/contracts/BasicMathLib.sol",
+			"ethpm-dependencies/ethereum-libraries-basic-math/contracts/BasicMathLib.sol"},
+	}
+	for _, v := range tests {
+		if got := ResolveImport(v.importer, v.importpath, r); got != v.want {
+			t.Fatalf("Got '%v', expected '%v'", got, v.want)
+		}
+	}
+}
+
+func TestBuildProjectInput(t *testing.T) {
+	b, err := ioutil.ReadFile("../../test/testdata/TokenLib.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]string{"./test/testdata/TokenLib.sol": string(b)}
+	si, err := BuildProjectInput("../..", sources, []string{"ethereum-libraries-basic-math"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := si.Sources["test/testdata/BasicMathLib.sol"]; !ok {
+		t.Fatalf("Imported source 'test/testdata/BasicMathLib.sol' missing from '%v'", si.Sources)
+	}
+	want := "ethereum-libraries-basic-math/=ethpm-dependencies/ethereum-libraries-basic-math/"
+	if got := si.Settings.Remappings[0]; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestCollectSourcesFromDependency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethpm-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	depdir := filepath.Join(dir, DependencyDir, "ethereum-libraries-basic-math", "contracts")
+	if err = os.MkdirAll(depdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lib := "pragma solidity ^0.4.24;\nlibrary BasicMathLib {}\n"
+	if err = ioutil.WriteFile(filepath.Join(depdir, "BasicMathLib.sol"), []byte(lib), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := map[string]string{
+		"./contracts/Token.sol": "import \"ethereum-libraries-basic-math/contracts/BasicMathLib.sol\";\ncontract Token {}\n",
+	}
+	got, err := CollectSources(dir, sources, DependencyRemappings([]string{"ethereum-libraries-basic-math"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["ethpm-dependencies/ethereum-libraries-basic-math/contracts/BasicMathLib.sol"] != lib {
+		t.Fatalf("Got '%v', expected the dependency source to be collected", got)
+	}
+
+	sources["./contracts/Token.sol"] = "import \"./Missing.sol\";\n"
+	_, err = CollectSources(dir, sources, nil)
+	if (err == nil) || !strings.HasPrefix(err.Error(), "Could not resolve import './Missing.sol' in 'contracts/Token.sol'") {
+		t.Fatalf("Got '%v', expected an unresolved import error", err)
+	}
+}
+
+func TestBuildProjectInputKeepsSettings(t *testing.T) {
+	settings := NewSolcSettings(false, 0)
+	settings.Remappings = []string{"owned/=ethpm-dependencies/owned/"}
+	sources := map[string]string{"./contracts/Token.sol": "contract Token {}\n"}
+	for i := 0; i < 2; i++ {
+		si, err := BuildProjectInput("", sources, []string{"owned", "math"}, settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"owned/=ethpm-dependencies/owned/", "math/=ethpm-dependencies/math/"}
+		if !reflect.DeepEqual(si.Settings.Remappings, want) {
+			t.Fatalf("Got '%v', expected '%v'", si.Settings.Remappings, want)
+		}
+	}
+	if len(settings.Remappings) != 1 {
+		t.Fatalf("Got '%v', expected the given settings to be unchanged", settings.Remappings)
+	}
+}
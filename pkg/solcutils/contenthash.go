@@ -0,0 +1,169 @@
+package solcutils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+)
+
+// ipfsChunkSize is the largest content 'ipfs add' stores in a single block
+const ipfsChunkSize = 262144
+
+// swarmChunkSize is the size of the chunks Swarm splits content into
+const swarmChunkSize = 4096
+
+// IPFSHash returns the CIDv0 'ipfs add' gives the content, stored as a single
+// UnixFS file block
+func IPFSHash(content []byte) (cid string, err error) {
+	multihash, err := IPFSMultihash(content)
+	if err != nil {
+		return
+	}
+	return IPFSCID(multihash)
+}
+
+// IPFSMultihash returns the hex encoded multihash of the content stored as a
+// single UnixFS file block, as solc embeds it in bytecode metadata
+func IPFSMultihash(content []byte) (multihash string, err error) {
+	if len(content) > ipfsChunkSize {
+		err = fmt.Errorf("IPFS hashes are only supported for content up to %v bytes", ipfsChunkSize)
+		return
+	}
+	// UnixFS Data message: Type File, Data, filesize
+	data := protoVarint(nil, 1, 2)
+	if len(content) > 0 {
+		data = protoBytes(data, 2, content)
+	}
+	data = protoVarint(data, 3, uint64(len(content)))
+	// dag-pb PBNode with the UnixFS message as its Data and no links
+	node := protoBytes(nil, 1, data)
+	sum := sha256.Sum256(node)
+	multihash = "1220" + hex.EncodeToString(sum[:])
+	return
+}
+
+// Bzzr0Hash returns the Swarm hash solc embeds as 'bzzr0' in bytecode metadata
+func Bzzr0Hash(content []byte) string {
+	return hex.EncodeToString(bzzr0Hash(content))
+}
+
+// Bzzr1Hash returns the Swarm binary merkle tree hash solc embeds as 'bzzr1' in
+// bytecode metadata
+func Bzzr1Hash(content []byte) string {
+	return hex.EncodeToString(bzzr1Hash(content, false))
+}
+
+// VerifyMetadataHash takes the metadata decoded from bytecode and the compiler
+// metadata json it references, then returns an error unless the content has
+// the IPFS or Swarm hash embedded in the bytecode
+func VerifyMetadataHash(md *bytecode.Metadata, content []byte) (err error) {
+	var got, want string
+	switch {
+	case md.IPFS != "":
+		want = md.IPFS
+		got, err = IPFSMultihash(content)
+	case md.Bzzr1 != "":
+		want, got = md.Bzzr1, Bzzr1Hash(content)
+	case md.Bzzr0 != "":
+		want, got = md.Bzzr0, Bzzr0Hash(content)
+	default:
+		err = fmt.Errorf("Bytecode metadata has no IPFS or Swarm hash")
+	}
+	if err != nil {
+		return
+	}
+	if got != strings.ToLower(strings.TrimPrefix(want, "0x")) {
+		err = fmt.Errorf("Metadata hash is '%v', expected '%v'", got, want)
+	}
+	return
+}
+
+// bzzr0Hash hashes the content as a tree of chunks, where every node is the
+// keccak256 hash of its little endian size followed by its data, or by the
+// hashes of its children
+func bzzr0Hash(content []byte) []byte {
+	data := content
+	if len(content) > swarmChunkSize {
+		size := swarmSubtreeSize(len(content))
+		data = nil
+		for i := 0; i < len(content); i += size {
+			end := i + size
+			if end > len(content) {
+				end = len(content)
+			}
+			data = append(data, bzzr0Hash(content[i:end])...)
+		}
+	}
+	return keccak256(swarmSpan(len(content)), data)
+}
+
+// bzzr1Hash hashes the content as bzzr0Hash does, but every node hashes its
+// data with a binary merkle tree over a zero padded chunk. A full chunk below a
+// level representing more than a chunk is hashed as an intermediate node.
+func bzzr1Hash(content []byte, intermediate bool) []byte {
+	data := content
+	if (len(content) > swarmChunkSize) || ((len(content) == swarmChunkSize) && intermediate) {
+		size := swarmSubtreeSize(len(content))
+		data = nil
+		for i := 0; i < len(content); i += size {
+			end := i + size
+			if end > len(content) {
+				end = len(content)
+			}
+			data = append(data, bzzr1Hash(content[i:end], size > swarmChunkSize)...)
+		}
+	}
+	chunk := make([]byte, swarmChunkSize)
+	copy(chunk, data)
+	return keccak256(swarmSpan(len(content)), bmtHash(chunk))
+}
+
+// swarmSubtreeSize returns the size of content each child of a node holding
+// size bytes represents
+func swarmSubtreeSize(size int) (subtree int) {
+	subtree = swarmChunkSize
+	for subtree*(swarmChunkSize/32) < size {
+		subtree *= swarmChunkSize / 32
+	}
+	return
+}
+
+func bmtHash(data []byte) []byte {
+	if len(data) <= 64 {
+		return keccak256(data)
+	}
+	mid := len(data) / 2
+	return keccak256(bmtHash(data[:mid]), bmtHash(data[mid:]))
+}
+
+func swarmSpan(size int) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(size))
+	return b
+}
+
+func keccak256(data ...[]byte) []byte {
+	hw := sha3.NewKeccak256()
+	for _, d := range data {
+		hw.Write(d)
+	}
+	return hw.Sum(nil)
+}
+
+func protoVarint(b []byte, field int, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	b = append(b, buf[:binary.PutUvarint(buf, uint64(field<<3))]...)
+	return append(b, buf[:binary.PutUvarint(buf, v)]...)
+}
+
+func protoBytes(b []byte, field int, v []byte) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	b = append(b, buf[:binary.PutUvarint(buf, uint64(field<<3|2))]...)
+	b = append(b, buf[:binary.PutUvarint(buf, uint64(len(v)))]...)
+	return append(b, v...)
+}
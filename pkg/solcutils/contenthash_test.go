@@ -0,0 +1,57 @@
+package solcutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+)
+
+func TestIPFSHash(t *testing.T) {
+	got, err := IPFSHash([]byte("hello world\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	if _, err = IPFSHash(make([]byte, ipfsChunkSize+1)); err == nil {
+		t.Fatal("Got <nil>, expected an error for content larger than a block")
+	}
+}
+
+func TestBzzr0Hash(t *testing.T) {
+	// The hash of empty content is the keccak256 hash of its eight byte size
+	if got, want := Bzzr0Hash(nil), "011b4d03dd8c01f1049143cf9c4c817e4b167f1d1b83e5c6f0f10d89ba1e7bce"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	content := []byte(strings.Repeat("a", swarmChunkSize+1))
+	want := keccak256(swarmSpan(len(content)), bzzr0Hash(content[:swarmChunkSize]), bzzr0Hash(content[swarmChunkSize:]))
+	if got := bzzr0Hash(content); string(got) != string(want) {
+		t.Fatalf("Got '%x', expected '%x'", got, want)
+	}
+}
+
+func TestVerifyMetadataHash(t *testing.T) {
+	content := []byte(testMetadata())
+	multihash, err := IPFSMultihash(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []*bytecode.Metadata{
+		{IPFS: multihash},
+		{Bzzr0: Bzzr0Hash(content)},
+		{Bzzr1: Bzzr1Hash(content)},
+	}
+	for _, md := range tests {
+		if err = VerifyMetadataHash(md, content); err != nil {
+			t.Fatalf("Got '%v', expected <nil> for '%+v'", err, md)
+		}
+		if err = VerifyMetadataHash(md, append(content, ' ')); err == nil {
+			t.Fatalf("Got <nil>, expected a mismatch for '%+v'", md)
+		}
+	}
+	if err = VerifyMetadataHash(&bytecode.Metadata{}, content); err == nil {
+		t.Fatal("Got <nil>, expected an error for metadata without a hash")
+	}
+}
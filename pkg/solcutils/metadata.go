@@ -0,0 +1,260 @@
+package solcutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+)
+
+// Default gateways used to fetch compiler metadata
+const (
+	DefaultIPFSGateway  = "https://ipfs.io"
+	DefaultSwarmGateway = "https://swarm-gateways.net"
+)
+
+// DefaultTimeout is how long downloads from a gateway or a mirror may take
+const DefaultTimeout = time.Minute
+
+// MetadataSource is a source listed in the compiler metadata. The content is
+// only present if the contract was compiled with useLiteralContent.
+type MetadataSource struct {
+	Content   string   `json:"content,omitempty"`
+	Keccak256 string   `json:"keccak256,omitempty"`
+	License   string   `json:"license,omitempty"`
+	URLs      []string `json:"urls,omitempty"`
+}
+
+// MetadataSettings is the settings object of the compiler metadata. Libraries
+// are keyed by "sourceUnitName:LibraryName" and the compilation target maps
+// the source unit name of the compiled contract to its name.
+type MetadataSettings struct {
+	CompilationTarget map[string]string     `json:"compilationTarget,omitempty"`
+	EVMVersion        string                `json:"evmVersion,omitempty"`
+	Libraries         map[string]string     `json:"libraries,omitempty"`
+	Metadata          *SolcMetadataSettings `json:"metadata,omitempty"`
+	Optimizer         *SolcOptimizer        `json:"optimizer,omitempty"`
+	Remappings        []string              `json:"remappings,omitempty"`
+	ViaIR             bool                  `json:"viaIR,omitempty"`
+}
+
+// CompilerMetadata is the metadata json solc embeds the hash of in bytecode
+// https://solidity.readthedocs.io/en/latest/metadata.html
+type CompilerMetadata struct {
+	Compiler struct {
+		Version string `json:"version,omitempty"`
+	} `json:"compiler"`
+	Language string                     `json:"language,omitempty"`
+	Output   json.RawMessage            `json:"output,omitempty"`
+	Settings *MetadataSettings          `json:"settings,omitempty"`
+	Sources  map[string]*MetadataSource `json:"sources,omitempty"`
+	Version  int                        `json:"version,omitempty"`
+}
+
+// MetadataGateway holds the http gateways used to fetch metadata stored on
+// IPFS or Swarm, and the client used to fetch it. A nil client uses one with
+// DefaultTimeout.
+type MetadataGateway struct {
+	Client *http.Client
+	IPFS   string
+	Swarm  string
+}
+
+// NewMetadataGateway takes the urls of an IPFS and a Swarm http gateway, where
+// empty values are replaced with the defaults, and returns a MetadataGateway
+func NewMetadataGateway(ipfs string, swarm string) *MetadataGateway {
+	if ipfs == "" {
+		ipfs = DefaultIPFSGateway
+	}
+	if swarm == "" {
+		swarm = DefaultSwarmGateway
+	}
+	return &MetadataGateway{
+		Client: &http.Client{Timeout: DefaultTimeout},
+		IPFS:   strings.TrimSuffix(ipfs, "/"),
+		Swarm:  strings.TrimSuffix(swarm, "/"),
+	}
+}
+
+// client returns the client of the gateway, or one with DefaultTimeout
+func (g *MetadataGateway) client() *http.Client {
+	if g.Client == nil {
+		return &http.Client{Timeout: DefaultTimeout}
+	}
+	return g.Client
+}
+
+// URL returns the location of the metadata json referenced by the bytecode
+// metadata, preferring IPFS over Swarm
+func (g *MetadataGateway) URL(md *bytecode.Metadata) (uri string, err error) {
+	switch {
+	case md.IPFS != "":
+		var cid string
+		if cid, err = IPFSCID(md.IPFS); err == nil {
+			uri = g.IPFS + "/ipfs/" + cid
+		}
+	case md.Bzzr1 != "":
+		uri = g.Swarm + "/bzz-raw:/" + md.Bzzr1
+	case md.Bzzr0 != "":
+		uri = g.Swarm + "/bzz-raw:/" + md.Bzzr0
+	default:
+		err = fmt.Errorf("Bytecode metadata has no IPFS or Swarm hash")
+	}
+	return
+}
+
+// Fetch takes the metadata decoded from bytecode and returns the compiler
+// metadata json it references. The json must have the hash embedded in the
+// bytecode, and its compiler version must match the solc version recorded
+// alongside it, if there is one.
+func (g *MetadataGateway) Fetch(md *bytecode.Metadata) (cm *CompilerMetadata, err error) {
+	uri, err := g.URL(md)
+	if err != nil {
+		return
+	}
+	resp, err := g.client().Get(uri)
+	if err != nil {
+		err = fmt.Errorf("Error fetching metadata: '%v'", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Got status '%v' for '%v'", resp.Status, uri)
+		return
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		err = fmt.Errorf("Error reading metadata: '%v'", err)
+		return
+	}
+	if err = VerifyMetadataHash(md, b); err != nil {
+		err = fmt.Errorf("Metadata from '%v' does not match the bytecode: '%v'", uri, err)
+		return
+	}
+	if cm, err = ParseCompilerMetadata(string(b)); err != nil {
+		return
+	}
+	if (md.Solc != "") && !VersionMatches(cm.Compiler.Version, md.Solc) {
+		cm, err = nil, fmt.Errorf("Metadata compiler version '%v' does not match '%v' in the bytecode", cm.Compiler.Version, md.Solc)
+	}
+	return
+}
+
+// ParseCompilerMetadata takes a compiler metadata json string and returns the
+// CompilerMetadata struct
+func ParseCompilerMetadata(jsonstring string) (cm *CompilerMetadata, err error) {
+	if err = json.Unmarshal([]byte(jsonstring), &cm); err != nil {
+		err = fmt.Errorf("Error parsing compiler metadata: '%v'", err)
+		return
+	}
+	if cm == nil {
+		err = fmt.Errorf("Empty compiler metadata")
+	}
+	return
+}
+
+// CheckSources takes source content keyed by source unit name and makes sure
+// every metadata source is either in it or has literal content, and that the
+// content matches the keccak256 hash recorded in the metadata. It returns the
+// content of every metadata source.
+func (cm *CompilerMetadata) CheckSources(sources map[string]string) (content map[string]string, err error) {
+	content = make(map[string]string)
+	names := make([]string, 0, len(cm.Sources))
+	for k := range cm.Sources {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ms := cm.Sources[name]
+		c, ok := sources[name]
+		if !ok {
+			if ms.Content == "" {
+				err = fmt.Errorf("No content for source '%v'", name)
+				return
+			}
+			c = ms.Content
+		}
+		if got := keccak256Hex(c); got != strings.ToLower(strings.TrimPrefix(ms.Keccak256, "0x")) {
+			err = fmt.Errorf("keccak256 of source '%v' is '0x%v', expected '%v'", name, got, ms.Keccak256)
+			return
+		}
+		content[name] = c
+	}
+	return
+}
+
+// StandardInput takes source content keyed by source unit name, checks it with
+// CheckSources and returns the standard json input which reproduces the
+// compilation the metadata describes
+func (cm *CompilerMetadata) StandardInput(sources map[string]string) (si *StandardInput, err error) {
+	content, err := cm.CheckSources(sources)
+	if err != nil {
+		return
+	}
+	settings := NewSolcSettings(false, 0)
+	if s := cm.Settings; s != nil {
+		settings.EVMVersion = s.EVMVersion
+		settings.Metadata = s.Metadata
+		settings.Optimizer = s.Optimizer
+		settings.Remappings = s.Remappings
+		settings.ViaIR = s.ViaIR
+		for k, v := range s.Libraries {
+			// Libraries without a source unit were given by name only
+			source, name := "", k
+			if i := strings.LastIndex(k, ":"); i >= 0 {
+				source, name = k[:i], k[i+1:]
+			}
+			settings.AddLibrary(source, name, v)
+		}
+	}
+	language := cm.Language
+	if language == "" {
+		language = "Solidity"
+	}
+	si = &StandardInput{Language: language, Sources: make(map[string]map[string]string), Settings: settings}
+	for k, v := range content {
+		si.Sources[k] = map[string]string{"content": v}
+	}
+	return
+}
+
+// IPFSCID takes a hex encoded IPFS multihash, as found in bytecode metadata,
+// and returns its base58 encoded CIDv0, such as "Qm..."
+func IPFSCID(multihash string) (cid string, err error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(multihash, "0x"))
+	if err != nil {
+		err = fmt.Errorf("Invalid multihash '%v': '%v'", multihash, err)
+		return
+	}
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	cid = string(out)
+	return
+}
+
+func keccak256Hex(s string) string {
+	return hex.EncodeToString(keccak256([]byte(s)))
+}
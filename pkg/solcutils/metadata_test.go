@@ -0,0 +1,107 @@
+package solcutils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+)
+
+const testSource = "pragma solidity ^0.5.0;\ncontract A {}\n"
+
+func testMetadata() string {
+	return fmt.Sprintf(`{"compiler":{"version":"0.5.17+commit.d19bba13"},"language":"Solidity","output":{},`+
+		`"settings":{"compilationTarget":{"contracts/A.sol":"A"},"evmVersion":"istanbul",`+
+		`"libraries":{"contracts/Lib.sol:Lib":"0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0"},`+
+		`"optimizer":{"enabled":true,"runs":200},"remappings":["owned/=ethpm-dependencies/owned/"]},`+
+		`"sources":{"contracts/A.sol":{"keccak256":"0x%v","urls":["dweb:/ipfs/QmA"]}},"version":1}`,
+		keccak256Hex(testSource))
+}
+
+func TestIPFSCID(t *testing.T) {
+	got, err := IPFSCID("1220" + strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "QmZtnFaddFtzGNT8BxdHVbQrhSFdq1pWxud5z4fA4kxfDt"; got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+}
+
+func TestFetchMetadata(t *testing.T) {
+	multihash, err := IPFSMultihash([]byte(testMetadata()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cid, err := IPFSCID(multihash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := &bytecode.Metadata{IPFS: multihash, Solc: "0.5.17"}
+	served := testMetadata()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/"+cid {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, served)
+	}))
+	defer server.Close()
+
+	cm, err := NewMetadataGateway(server.URL+"/", "").Fetch(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Compiler.Version != "0.5.17+commit.d19bba13" {
+		t.Fatalf("Got '%v', expected '%v'", cm.Compiler.Version, "0.5.17+commit.d19bba13")
+	}
+	si, err := cm.StandardInput(map[string]string{"contracts/A.sol": testSource})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if si.Sources["contracts/A.sol"]["content"] != testSource {
+		t.Fatalf("Got '%v', expected '%v'", si.Sources["contracts/A.sol"]["content"], testSource)
+	}
+	if (si.Settings.EVMVersion != "istanbul") || !si.Settings.Optimizer.Enabled || (si.Settings.Optimizer.Runs != 200) {
+		t.Fatalf("Got '%+v', expected the metadata settings", si.Settings)
+	}
+	if got := si.Settings.Libraries["contracts/Lib.sol"]["Lib"]; got != "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0" {
+		t.Fatalf("Got '%v', expected '%v'", got, "0x4f5b11c860b37b68de6d14fb7e7b5f18a9a1bdc0")
+	}
+	if len(si.Settings.Remappings) != 1 {
+		t.Fatalf("Got '%v', expected '%v'", si.Settings.Remappings, cm.Settings.Remappings)
+	}
+
+	if _, err = cm.StandardInput(map[string]string{"contracts/A.sol": testSource + " "}); err == nil {
+		t.Fatalf("Got '%v', expected a keccak256 mismatch", err)
+	}
+	if _, err = cm.StandardInput(nil); err == nil {
+		t.Fatalf("Got '%v', expected an error for a missing source", err)
+	}
+	if _, err = NewMetadataGateway(server.URL, "").Fetch(&bytecode.Metadata{IPFS: "1220" + strings.Repeat("cd", 32)}); err == nil {
+		t.Fatalf("Got '%v', expected an error for unknown metadata", err)
+	}
+	if _, err = NewMetadataGateway(server.URL, "").Fetch(&bytecode.Metadata{IPFS: multihash, Solc: "0.5.16"}); err == nil {
+		t.Fatalf("Got '%v', expected an error for a different compiler version", err)
+	}
+	served = strings.Replace(served, `"runs":200`, `"runs":1`, 1)
+	if _, err = NewMetadataGateway(server.URL, "").Fetch(md); err == nil {
+		t.Fatalf("Got '%v', expected an error for metadata not matching its hash", err)
+	}
+
+	hung := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hung
+	}))
+	defer slow.Close()
+	defer close(hung)
+	gateway := NewMetadataGateway(slow.URL, "")
+	gateway.Client.Timeout = 10 * time.Millisecond
+	if _, err = gateway.Fetch(md); (err == nil) || !strings.Contains(err.Error(), "Error fetching metadata") {
+		t.Fatalf("Got '%v', expected a timeout from an unresponsive gateway", err)
+	}
+}
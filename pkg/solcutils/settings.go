@@ -0,0 +1,80 @@
+package solcutils
+
+// SolcOptimizerDetails switches individual optimizer components on or off. A nil
+// value leaves the compiler's default for that component in place.
+// https://solidity.readthedocs.io/en/latest/using-the-compiler.html#input-description
+type SolcOptimizerDetails struct {
+	ConstantOptimizer *bool           `json:"constantOptimizer,omitempty"`
+	CSE               *bool           `json:"cse,omitempty"`
+	Deduplicate       *bool           `json:"deduplicate,omitempty"`
+	Inliner           *bool           `json:"inliner,omitempty"`
+	JumpdestRemover   *bool           `json:"jumpdestRemover,omitempty"`
+	OrderLiterals     *bool           `json:"orderLiterals,omitempty"`
+	Peephole          *bool           `json:"peephole,omitempty"`
+	Yul               *bool           `json:"yul,omitempty"`
+	YulDetails        *SolcYulDetails `json:"yulDetails,omitempty"`
+}
+
+// SolcYulDetails represents the yul optimizer settings in a standard json input
+type SolcYulDetails struct {
+	OptimizerSteps  string `json:"optimizerSteps,omitempty"`
+	StackAllocation bool   `json:"stackAllocation,omitempty"`
+}
+
+// SolcMetadataSettings represents the metadata settings in a standard json input
+type SolcMetadataSettings struct {
+	AppendCBOR        *bool  `json:"appendCBOR,omitempty"`
+	BytecodeHash      string `json:"bytecodeHash,omitempty"`
+	UseLiteralContent bool   `json:"useLiteralContent,omitempty"`
+}
+
+// DefaultOutputSelection is the output requested for every contract unless other
+// settings are given. It contains everything needed to build a complete contract
+// type: the abi, natspec, metadata, method identifiers, and both bytecode objects
+// along with their source maps.
+var DefaultOutputSelection = []string{
+	"abi",
+	"devdoc",
+	"userdoc",
+	"metadata",
+	"evm.bytecode",
+	"evm.deployedBytecode",
+	"evm.methodIdentifiers",
+}
+
+// NewSolcSettings takes the optimize setting and the number of optimizer runs
+// and returns settings requesting DefaultOutputSelection for every contract
+func NewSolcSettings(optimize bool, runs int) *SolcSettings {
+	selections := make([]string, len(DefaultOutputSelection))
+	copy(selections, DefaultOutputSelection)
+	return &SolcSettings{
+		Optimizer: &SolcOptimizer{
+			Enabled: optimize,
+			Runs:    runs,
+		},
+		OutputSelection: map[string]map[string][]string{
+			"*": {"*": selections},
+		},
+	}
+}
+
+// SetOutputSelection replaces the output requested for every contract
+func (s *SolcSettings) SetOutputSelection(selections ...string) {
+	s.OutputSelection = map[string]map[string][]string{
+		"*": {"*": selections},
+	}
+	return
+}
+
+// AddLibrary takes a source unit name, a library name and the address it is
+// deployed at, then adds it to the libraries the compiler links against
+func (s *SolcSettings) AddLibrary(source string, name string, address string) {
+	if len(s.Libraries) == 0 {
+		s.Libraries = make(map[string]map[string]string)
+	}
+	if len(s.Libraries[source]) == 0 {
+		s.Libraries[source] = make(map[string]string)
+	}
+	s.Libraries[source][name] = address
+	return
+}
@@ -0,0 +1,30 @@
+package solcutils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewSolcSettings(t *testing.T) {
+	ss := NewSolcSettings(true, 200)
+	ss.EVMVersion = "byzantium"
+	ss.AddLibrary("contracts/Token.sol", "BasicMathLib", "0x692a70d2e424a56d2c6c27aa97d1a86395877b3a")
+	b, err := json.Marshal(ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"evmVersion":"byzantium","libraries":{"contracts/Token.sol":{"BasicMathLib":"0x692a70d2e424a56d2c6c27aa97d1a86395877b3a"}},` +
+		`"optimizer":{"enabled":true,"runs":200},"outputSelection":{"*":{"*":["abi","devdoc","userdoc","metadata",` +
+		`"evm.bytecode","evm.deployedBytecode","evm.methodIdentifiers"]}}}`
+	if got := string(b); got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+
+	ss.SetOutputSelection("abi")
+	if got := ss.OutputSelection["*"]["*"]; (len(got) != 1) || (got[0] != "abi") {
+		t.Fatalf("Got '%v', expected '[abi]'", got)
+	}
+	if DefaultOutputSelection[0] != "abi" || len(DefaultOutputSelection) != 7 {
+		t.Fatalf("Got '%v', expected DefaultOutputSelection to be unchanged", DefaultOutputSelection)
+	}
+}
@@ -0,0 +1,259 @@
+package solcutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/ethpm/ethpm-go/pkg/bytecode"
+)
+
+// DefaultSolcMirror is the location of the official solc release binaries
+const DefaultSolcMirror = "https://binaries.soliditylang.org"
+
+// solcDownloadTimeout allows for downloading binaries of several megabytes over
+// a slow connection
+const solcDownloadTimeout = 10 * DefaultTimeout
+
+// SolcBuild is a single entry in the builds of a solc release list
+type SolcBuild struct {
+	Build       string `json:"build,omitempty"`
+	Keccak256   string `json:"keccak256,omitempty"`
+	LongVersion string `json:"longVersion,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Sha256      string `json:"sha256,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// SolcList represents the list.json file found in a solc release mirror
+type SolcList struct {
+	Builds        []*SolcBuild      `json:"builds,omitempty"`
+	LatestRelease string            `json:"latestRelease,omitempty"`
+	Releases      map[string]string `json:"releases,omitempty"`
+}
+
+// SolcManager installs solc binaries from a mirror into a local cache and
+// returns the path of the binary for a requested version. The mirror is either
+// a url or a local directory containing a list.json file and the binaries it
+// lists, laid out like https://binaries.soliditylang.org/linux-amd64. The
+// release list is fetched once and kept for the life of the manager. A nil
+// client downloads with a timeout of ten minutes.
+type SolcManager struct {
+	CacheDir string
+	Client   *http.Client
+	Mirror   string
+
+	list *SolcList
+}
+
+// NewSolcManager takes the cache directory and the mirror to install from. An
+// empty cache directory defaults to ".ethpm/solc" in the user's home directory,
+// and an empty mirror defaults to the platform directory of DefaultSolcMirror.
+func NewSolcManager(cachedir string, mirror string) (m *SolcManager, err error) {
+	if cachedir == "" {
+		usr, retErr := user.Current()
+		if retErr != nil {
+			err = fmt.Errorf("Could not get home directory: '%v'", retErr)
+			return
+		}
+		cachedir = filepath.Join(usr.HomeDir, ".ethpm", "solc")
+	}
+	if mirror == "" {
+		mirror = DefaultSolcMirror + "/" + platformDir()
+	}
+	m = &SolcManager{
+		CacheDir: cachedir,
+		Client:   &http.Client{Timeout: solcDownloadTimeout},
+		Mirror:   strings.TrimSuffix(mirror, "/"),
+	}
+	return
+}
+
+// List returns the release list of the mirror, fetching and parsing it on the
+// first call
+func (m *SolcManager) List() (list *SolcList, err error) {
+	if m.list != nil {
+		list = m.list
+		return
+	}
+	b, err := m.fetch("list.json")
+	if err != nil {
+		err = fmt.Errorf("Could not get solc release list: '%v'", err)
+		return
+	}
+	if err = json.Unmarshal(b, &list); err != nil {
+		err = fmt.Errorf("Error parsing solc release list: '%v'", err)
+		return
+	}
+	m.list = list
+	return
+}
+
+// FindBuild takes a version, either a release such as "0.4.24" or the version
+// recorded in a compiler information object such as "0.4.24+commit.e67f0147",
+// and returns the matching build from the release list
+func (list *SolcList) FindBuild(version string) (build *SolcBuild, err error) {
+	version = NormalizeVersion(version)
+	for _, b := range list.Builds {
+		if (b.LongVersion == version) || (b.Version == version) {
+			build = b
+			return
+		}
+	}
+	err = fmt.Errorf("No solc build found for version '%v'", version)
+	return
+}
+
+// Install takes a version and returns the path of the verified binary for it,
+// downloading it from the mirror into the cache directory if it is not already
+// installed. Cached binaries are verified again before they are returned.
+func (m *SolcManager) Install(version string) (binpath string, err error) {
+	list, err := m.List()
+	if err != nil {
+		return
+	}
+	build, err := list.FindBuild(version)
+	if err != nil {
+		return
+	}
+	binpath = filepath.Join(m.CacheDir, "solc-"+build.LongVersion)
+	if b, retErr := ioutil.ReadFile(binpath); retErr == nil {
+		if err = VerifyBuild(b, build); err != nil {
+			err = fmt.Errorf("Cached solc '%v' failed verification: '%v'", binpath, err)
+		}
+		return
+	}
+	b, err := m.fetch(build.Path)
+	if err != nil {
+		err = fmt.Errorf("Could not download solc '%v': '%v'", build.LongVersion, err)
+		return
+	}
+	if err = VerifyBuild(b, build); err != nil {
+		err = fmt.Errorf("Downloaded solc '%v' failed verification: '%v'", build.LongVersion, err)
+		return
+	}
+	if err = os.MkdirAll(m.CacheDir, 0755); err != nil {
+		err = fmt.Errorf("Could not create solc cache directory: '%v'", err)
+		return
+	}
+	if err = ioutil.WriteFile(binpath, b, 0755); err != nil {
+		err = fmt.Errorf("Could not write solc binary: '%v'", err)
+	}
+	return
+}
+
+// Resolve takes the name of a compiler and the version recorded for it in a
+// package, then returns the compiler to run. Solc with a recorded version is
+// installed and its path returned, otherwise the compiler name is returned as is.
+func (m *SolcManager) Resolve(compiler string, version string) (resolved string, err error) {
+	resolved = compiler
+	if (m == nil) || (version == "") || (filepath.Base(compiler) != "solc") {
+		return
+	}
+	if resolved, err = m.Install(version); err != nil {
+		return
+	}
+	installed, err := bytecode.GetVersion(resolved)
+	if err != nil {
+		return
+	}
+	if !VersionMatches(installed, version) {
+		err = fmt.Errorf("Installed solc reports version '%v', expected '%v'", installed, version)
+	}
+	return
+}
+
+// NormalizeVersion takes a solc version, which may include a platform suffix
+// such as "0.4.24+commit.e67f0147.Linux.g++", and returns it without the suffix
+func NormalizeVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	i := strings.Index(version, "+commit.")
+	if i < 0 {
+		return version
+	}
+	commit := version[i+len("+commit."):]
+	if j := strings.Index(commit, "."); j >= 0 {
+		commit = commit[:j]
+	}
+	return version[:i] + "+commit." + commit
+}
+
+// VersionMatches takes the version reported by a compiler and a requested
+// version, and returns true if they are the same version. A requested version
+// without a commit, such as "0.4.24", matches any commit of that release.
+func VersionMatches(installed string, requested string) bool {
+	installed, requested = NormalizeVersion(installed), NormalizeVersion(requested)
+	if !strings.Contains(requested, "+commit.") {
+		installed = strings.SplitN(installed, "+", 2)[0]
+	}
+	return installed == requested
+}
+
+// VerifyBuild takes the content of a solc binary and the build it is expected
+// to be, then checks it against the sha256 and keccak256 checksums of the build
+func VerifyBuild(b []byte, build *SolcBuild) (err error) {
+	if (build.Sha256 == "") && (build.Keccak256 == "") {
+		err = fmt.Errorf("Build '%v' has no checksums", build.LongVersion)
+		return
+	}
+	if build.Sha256 != "" {
+		sum := sha256.Sum256(b)
+		if got := hex.EncodeToString(sum[:]); got != trimHex(build.Sha256) {
+			err = fmt.Errorf("sha256 mismatch, got '%v', expected '%v'", got, trimHex(build.Sha256))
+			return
+		}
+	}
+	if build.Keccak256 != "" {
+		hw := sha3.NewKeccak256()
+		hw.Write(b)
+		if got := hex.EncodeToString(hw.Sum(nil)); got != trimHex(build.Keccak256) {
+			err = fmt.Errorf("keccak256 mismatch, got '%v', expected '%v'", got, trimHex(build.Keccak256))
+		}
+	}
+	return
+}
+
+// fetch reads a file relative to the mirror, which is either a url or a directory
+func (m *SolcManager) fetch(name string) (b []byte, err error) {
+	if strings.HasPrefix(m.Mirror, "http://") || strings.HasPrefix(m.Mirror, "https://") {
+		client := m.Client
+		if client == nil {
+			client = &http.Client{Timeout: solcDownloadTimeout}
+		}
+		resp, retErr := client.Get(m.Mirror + "/" + name)
+		if retErr != nil {
+			err = retErr
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("Got status '%v' for '%v'", resp.Status, m.Mirror+"/"+name)
+			return
+		}
+		return ioutil.ReadAll(resp.Body)
+	}
+	return ioutil.ReadFile(filepath.Join(m.Mirror, filepath.FromSlash(name)))
+}
+
+func trimHex(s string) string {
+	return strings.ToLower(strings.TrimPrefix(s, "0x"))
+}
+
+func platformDir() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macosx-amd64"
+	case "windows":
+		return "windows-amd64"
+	}
+	return "linux-amd64"
+}
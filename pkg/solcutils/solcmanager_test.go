@@ -0,0 +1,131 @@
+package solcutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+)
+
+func writeTestMirror(t *testing.T, dir string, bin []byte, sha string) {
+	hw := sha3.NewKeccak256()
+	hw.Write(bin)
+	list := SolcList{
+		Builds: []*SolcBuild{{
+			Path:        "solc-linux-amd64-v0.4.24+commit.e67f0147",
+			Version:     "0.4.24",
+			LongVersion: "0.4.24+commit.e67f0147",
+			Sha256:      "0x" + sha,
+			Keccak256:   "0x" + hex.EncodeToString(hw.Sum(nil)),
+		}},
+		LatestRelease: "0.4.24",
+		Releases:      map[string]string{"0.4.24": "solc-linux-amd64-v0.4.24+commit.e67f0147"},
+	}
+	b, _ := json.Marshal(list)
+	if err := ioutil.WriteFile(filepath.Join(dir, "list.json"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, list.Builds[0].Path), bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSolcManagerInstall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mirror := filepath.Join(dir, "mirror")
+	if err = os.MkdirAll(mirror, 0755); err != nil {
+		t.Fatal(err)
+	}
+	bin := []byte("#!/bin/sh\necho 'solc, the solidity compiler commandline interface'\necho 'Version: 0.4.24+commit.e67f0147.Linux.g++'\n")
+	sum := sha256.Sum256(bin)
+	writeTestMirror(t, mirror, bin, hex.EncodeToString(sum[:]))
+
+	m, err := NewSolcManager(filepath.Join(dir, "cache"), mirror)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := m.Install("0.4.24+commit.e67f0147.Linux.g++")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "cache", "solc-0.4.24+commit.e67f0147")
+	if got != want {
+		t.Fatalf("Got '%v', expected '%v'", got, want)
+	}
+	// The release list is only read once
+	if err = os.Remove(filepath.Join(mirror, "list.json")); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"0.4.24+commit.e67f0147", "0.4.24"} {
+		if got, err = m.Resolve("solc", version); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("Got '%v', expected '%v'", got, want)
+		}
+	}
+
+	if err = ioutil.WriteFile(want, []byte("tampered"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Install("0.4.24"); (err == nil) || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("Got '%v', expected a sha256 mismatch", err)
+	}
+	if _, err = m.Install("0.5.0"); (err == nil) || (err.Error() != "No solc build found for version '0.5.0'") {
+		t.Fatalf("Got '%v', expected 'No solc build found for version '0.5.0''", err)
+	}
+}
+
+func TestVerifyBuild(t *testing.T) {
+	bin := []byte("solc")
+	sum := sha256.Sum256(bin)
+	build := &SolcBuild{LongVersion: "0.4.24+commit.e67f0147", Sha256: hex.EncodeToString(sum[:])}
+	if err := VerifyBuild(bin, build); err != nil {
+		t.Fatalf("Got '%v', expected <nil>", err)
+	}
+	build.Keccak256 = "0x00"
+	if err := VerifyBuild(bin, build); (err == nil) || !strings.HasPrefix(err.Error(), "keccak256 mismatch") {
+		t.Fatalf("Got '%v', expected a keccak256 mismatch", err)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := map[string]string{
+		"0.4.24+commit.e67f0147.Linux.g++": "0.4.24+commit.e67f0147",
+		"v0.4.24+commit.e67f0147":          "0.4.24+commit.e67f0147",
+		"0.4.24":                           "0.4.24",
+	}
+	for k, v := range tests {
+		if got := NormalizeVersion(k); got != v {
+			t.Fatalf("Got '%v', expected '%v'", got, v)
+		}
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		installed string
+		requested string
+		want      bool
+	}{
+		{"0.4.24+commit.e67f0147.Linux.g++", "0.4.24+commit.e67f0147", true},
+		{"0.4.24+commit.e67f0147.Linux.g++", "0.4.24", true},
+		{"0.4.24+commit.e67f0147", "0.4.24+commit.00000000", false},
+		{"0.4.25+commit.59dbf8f1", "0.4.24", false},
+	}
+	for _, tt := range tests {
+		if got := VersionMatches(tt.installed, tt.requested); got != tt.want {
+			t.Fatalf("Got '%v' for '%v' and '%v', expected '%v'", got, tt.installed, tt.requested, tt.want)
+		}
+	}
+}